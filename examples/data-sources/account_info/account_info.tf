@@ -0,0 +1,24 @@
+terraform {
+    required_providers {
+        nats = {
+            source = "registry.terraform.io/AhmadElsagheer/nats"
+        }
+    }
+}
+
+provider "nats" {}
+
+data "nats_account_info" "current" {}
+
+resource "nats_stream" "orders_stream" {
+    name      = "orders"
+    subjects  = ["order.*"]
+    max_bytes = 1073741824
+
+    lifecycle {
+        precondition {
+            condition     = data.nats_account_info.current.limits.max_storage < 0 || data.nats_account_info.current.storage + 1073741824 <= 0.8 * data.nats_account_info.current.limits.max_storage
+            error_message = "Storage headroom must remain above 20%."
+        }
+    }
+}
@@ -0,0 +1,17 @@
+terraform {
+    required_providers {
+        nats = {
+            source = "registry.terraform.io/AhmadElsagheer/nats"
+        }
+    }
+}
+
+provider "nats" {}
+
+data "nats_consumers" "orders" {
+    stream_name = "orders"
+}
+
+output "orders_consumers" {
+    value = data.nats_consumers.orders.consumers[*].name
+}
@@ -0,0 +1,56 @@
+terraform {
+    required_providers {
+        nats = {
+            source = "registry.terraform.io/AhmadElsagheer/nats"
+        }
+    }
+}
+
+data "nats_server_config" "n1" {
+    server_name = "n1"
+    listen      = "0.0.0.0:4222"
+
+    jetstream = {
+        store_dir      = "/data/jetstream"
+        max_file_store = 10737418240
+    }
+
+    cluster = {
+        name   = "east"
+        listen = "0.0.0.0:6222"
+        routes = ["nats://n2:6222", "nats://n3:6222"]
+    }
+
+    accounts = {
+        ORDERS = {
+            jetstream = true
+            users = [{
+                user     = "orders"
+                password = var.orders_password
+                permissions = {
+                    pub_allow = ["orders.>"]
+                    sub_allow = ["orders.>", "_INBOX.>"]
+                }
+            }]
+        }
+        SYS = {
+            users = [{ user = "sys", password = var.sys_password }]
+        }
+    }
+    system_account = "SYS"
+}
+
+variable "orders_password" {
+    type      = string
+    sensitive = true
+}
+
+variable "sys_password" {
+    type      = string
+    sensitive = true
+}
+
+output "config" {
+    value     = data.nats_server_config.n1.config
+    sensitive = true
+}
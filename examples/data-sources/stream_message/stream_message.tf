@@ -0,0 +1,18 @@
+terraform {
+    required_providers {
+        nats = {
+            source = "registry.terraform.io/AhmadElsagheer/nats"
+        }
+    }
+}
+
+provider "nats" {}
+
+data "nats_stream_message" "us_config" {
+    stream_name     = "config"
+    last_by_subject = "config.region.us"
+}
+
+output "us_config" {
+    value = jsondecode(data.nats_stream_message.us_config.data)
+}
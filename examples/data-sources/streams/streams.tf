@@ -0,0 +1,24 @@
+terraform {
+    required_providers {
+        nats = {
+            source = "registry.terraform.io/AhmadElsagheer/nats"
+        }
+    }
+}
+
+provider "nats" {}
+
+data "nats_streams" "orders" {
+    name_regex = "^orders_"
+    metadata   = {
+        team = "orders"
+    }
+}
+
+resource "nats_consumer" "monitor" {
+    for_each = toset(data.nats_streams.orders.names)
+
+    stream_name = each.value
+    name        = "monitor"
+    mode        = "pull"
+}
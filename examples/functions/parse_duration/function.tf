@@ -0,0 +1,6 @@
+resource "nats_stream" "events" {
+    name             = "events"
+    subjects         = ["events.>"]
+    max_age          = provider::nats::parse_duration("2w")
+    duplicate_window = provider::nats::parse_duration("2m")
+}
@@ -0,0 +1,16 @@
+variable "consumer_filter" {
+    type = string
+}
+
+resource "nats_consumer" "eu_orders" {
+    stream_name     = nats_stream.orders.name
+    name            = "eu_orders"
+    filter_subjects = [var.consumer_filter]
+
+    lifecycle {
+        precondition {
+            condition     = provider::nats::subject_matches(var.consumer_filter, "orders.>")
+            error_message = "The filter must be within the orders stream subjects."
+        }
+    }
+}
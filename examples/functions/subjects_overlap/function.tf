@@ -0,0 +1,26 @@
+locals {
+    stream_subjects = {
+        orders   = ["orders.>"]
+        payments = ["payments.*", "orders.paid"]
+    }
+    overlaps = [
+        for pair in setproduct(keys(local.stream_subjects), keys(local.stream_subjects)) : pair
+        if pair[0] < pair[1] && anytrue([
+            for s in setproduct(local.stream_subjects[pair[0]], local.stream_subjects[pair[1]]) :
+            provider::nats::subjects_overlap(s[0], s[1])
+        ])
+    ]
+}
+
+resource "nats_stream" "streams" {
+    for_each = local.stream_subjects
+    name     = each.key
+    subjects = each.value
+
+    lifecycle {
+        precondition {
+            condition     = length(local.overlaps) == 0
+            error_message = "Streams ${jsonencode(local.overlaps)} have overlapping subjects."
+        }
+    }
+}
@@ -0,0 +1,7 @@
+locals {
+    # ["orders", "eu", "created"]
+    tokens = provider::nats::tokenize_subject("orders.eu.created")
+    region = local.tokens[1]
+    # orders.eu.>
+    region_subjects = join(".", concat(slice(local.tokens, 0, 2), [">"]))
+}
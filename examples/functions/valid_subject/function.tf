@@ -0,0 +1,8 @@
+variable "subject" {
+    type = string
+
+    validation {
+        condition     = provider::nats::valid_subject(var.subject)
+        error_message = "The subject must be a valid nats subject."
+    }
+}
@@ -1,3 +1,9 @@
 provider "nats" {
   url = "http://locahost:4222"
 }
+
+# Each alias maps onto an existing nats CLI context
+provider "nats" {
+  alias   = "billing"
+  context = "billing"
+}
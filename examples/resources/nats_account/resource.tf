@@ -0,0 +1,72 @@
+resource "nats_nkey" "orders_account" {
+    type = "account"
+}
+
+resource "nats_nkey" "app_team_signer" {
+    type = "account"
+}
+
+resource "nats_account" "orders" {
+    name            = "orders"
+    public_key      = nats_nkey.orders_account.public_key
+    signing_seed    = nats_nkey.operator.seed
+    max_connections = 100
+
+    jetstream_tiered_limits = {
+        R3 = {
+            disk_storage = 10737418240
+            streams      = 10
+        }
+    }
+
+    scoped_signing_keys = {
+        "app-team" = {
+            key = nats_nkey.app_team_signer.public_key
+            template = {
+                pub_allow         = ["orders.{{tag(team)}}.>"]
+                sub_allow         = ["_INBOX.>", "orders.{{tag(team)}}.>"]
+                max_subscriptions = 100
+            }
+        }
+    }
+
+    exports = [
+        {
+            name    = "orders"
+            subject = "orders.>"
+            type    = "stream"
+        }
+    ]
+}
+
+# Users of the apps account are authenticated by the service connecting as
+# the auth_service user
+resource "nats_nkey" "auth_account" {
+    type = "account"
+}
+
+resource "nats_nkey" "auth_service" {
+    type = "user"
+}
+
+resource "nats_nkey" "auth_xkey" {
+    type = "curve"
+}
+
+resource "nats_user" "auth_service" {
+    name         = "auth-service"
+    seed         = nats_nkey.auth_service.seed
+    signing_seed = nats_nkey.auth_account.seed
+}
+
+resource "nats_account" "auth" {
+    name         = "auth"
+    public_key   = nats_nkey.auth_account.public_key
+    signing_seed = nats_nkey.operator.seed
+
+    auth_callout = {
+        auth_users       = [nats_user.auth_service.public_key]
+        allowed_accounts = [nats_account.orders.public_key]
+        xkey             = nats_nkey.auth_xkey.public_key
+    }
+}
@@ -0,0 +1,9 @@
+provider "nats" {
+    url                  = "nats://localhost:4222"
+    system_account_creds = "/etc/nats/sys.creds"
+}
+
+resource "nats_account_jwt_push" "orders" {
+    jwt           = nats_account.orders.jwt
+    operator_seed = nats_nkey.operator.seed
+}
@@ -0,0 +1,7 @@
+resource "nats_nkey" "operator" {
+    type = "operator"
+}
+
+resource "nats_nkey" "orders_account" {
+    type = "account"
+}
@@ -0,0 +1,13 @@
+resource "nats_nkey" "operator" {
+    type = "operator"
+}
+
+resource "nats_nkey" "system_account" {
+    type = "account"
+}
+
+resource "nats_operator" "acme" {
+    name           = "acme"
+    seed           = nats_nkey.operator.seed
+    system_account = nats_nkey.system_account.public_key
+}
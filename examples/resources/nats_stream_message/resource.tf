@@ -0,0 +1,11 @@
+resource "nats_stream" "schemas" {
+    name     = "schemas"
+    subjects = ["schema.>"]
+}
+
+resource "nats_stream_message" "orders_schema" {
+    subject         = "schema.orders"
+    data            = jsonencode({ version = 1 })
+    headers         = { "Content-Type" = "application/json" }
+    expected_stream = nats_stream.schemas.name
+}
@@ -0,0 +1,10 @@
+resource "nats_stream_purge" "orders_created" {
+    stream_name = nats_stream.orders_stream.name
+    subject     = "order.created"
+    keep        = 100
+
+    # Purges again whenever the retention changes
+    triggers = {
+        retention = nats_stream.orders_stream.retention
+    }
+}
@@ -0,0 +1,16 @@
+resource "nats_stream_snapshot" "orders" {
+    stream_name = "orders"
+    path        = "${path.root}/snapshots/orders"
+
+    triggers = {
+        drill = "2024-q1"
+    }
+}
+
+# Restores the snapshot into another cluster
+resource "nats_stream" "orders_dr" {
+    provider              = nats.dr
+    name                  = "orders"
+    subjects              = ["order.*"]
+    restore_from_snapshot = nats_stream_snapshot.orders.path
+}
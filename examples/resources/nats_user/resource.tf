@@ -0,0 +1,37 @@
+resource "nats_nkey" "orders_service" {
+    type = "user"
+}
+
+resource "nats_user" "orders_service" {
+    name         = "orders-service"
+    seed         = nats_nkey.orders_service.seed
+    signing_seed = nats_nkey.orders_account.seed
+    pub_allow    = ["orders.>"]
+    sub_allow    = ["_INBOX.>"]
+
+    response_permissions = {
+        max_msgs = 1
+    }
+
+    source_networks = ["10.0.0.0/8"]
+    expires         = "2027-01-01T00:00:00Z"
+}
+
+# Users issued under a scope get the permissions of the scope template
+resource "nats_nkey" "billing_app" {
+    type = "user"
+}
+
+resource "nats_user" "billing_app" {
+    name         = "billing-app"
+    seed         = nats_nkey.billing_app.seed
+    signing_seed = nats_nkey.app_team_signer.seed
+    scope        = "app-team"
+    account_jwt  = nats_account.orders.jwt
+    tags         = ["team:billing"]
+}
+
+resource "local_sensitive_file" "orders_service_creds" {
+    filename = "orders-service.creds"
+    content  = nats_user.orders_service.creds
+}
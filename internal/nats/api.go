@@ -0,0 +1,69 @@
+package nats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// apiTimeout matches the default request timeout of a jetstream context.
+const apiTimeout = 5 * time.Second
+
+// apiError is the error body returned by the JetStream API.
+type apiError struct {
+	Code        int    `json:"code"`
+	ErrorCode   int    `json:"err_code"`
+	Description string `json:"description"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Description, e.ErrorCode)
+}
+
+type apiResponse struct {
+	Error *apiError `json:"error,omitempty"`
+}
+
+type apiPaged struct {
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// apiSubject returns the subject of the given JetStream API endpoint, e.g.
+// STREAM.LIST, honoring the configured domain or API prefix.
+func (c *client) apiSubject(endpoint string) string {
+	switch {
+	case c.config.JetStreamDomain != "":
+		return fmt.Sprintf("$JS.%s.API.%s", c.config.JetStreamDomain, endpoint)
+	case c.config.JetStreamAPIPrefix != "":
+		return strings.TrimSuffix(c.config.JetStreamAPIPrefix, ".") + "." + endpoint
+	default:
+		return "$JS.API." + endpoint
+	}
+}
+
+// apiRequest sends req to the given JetStream API endpoint and decodes the
+// reply into resp. A nil req sends an empty request.
+func (c *client) apiRequest(nc *nats.Conn, endpoint string, req any, resp any) error {
+	var data []byte
+	if req != nil {
+		var err error
+		data, err = json.Marshal(req)
+		if err != nil {
+			return err
+		}
+	}
+	msg, err := nc.Request(c.apiSubject(endpoint), data, apiTimeout)
+	if err != nil {
+		if errors.Is(err, nats.ErrNoResponders) {
+			return ErrJetStreamNotEnabled
+		}
+		return err
+	}
+	return json.Unmarshal(msg.Data, resp)
+}
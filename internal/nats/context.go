@@ -0,0 +1,82 @@
+package nats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// natsContext maps the context files written by the nats CLI.
+type natsContext struct {
+	URL                string `json:"url"`
+	Token              string `json:"token"`
+	User               string `json:"user"`
+	Password           string `json:"password"`
+	Creds              string `json:"creds"`
+	NKey               string `json:"nkey"`
+	Cert               string `json:"cert"`
+	Key                string `json:"key"`
+	CA                 string `json:"ca"`
+	JetStreamDomain    string `json:"jetstream_domain"`
+	JetStreamAPIPrefix string `json:"jetstream_api_prefix"`
+	InboxPrefix        string `json:"inbox_prefix"`
+}
+
+// LoadContext reads the nats CLI context with the given name from
+// $XDG_CONFIG_HOME/nats/context (~/.config/nats/context by default).
+func LoadContext(name string) (Config, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return Config{}, fmt.Errorf("invalid context name %q", name)
+	}
+	dir, err := contextDir()
+	if err != nil {
+		return Config{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read context %q: %w", name, err)
+	}
+	var nctx natsContext
+	if err := json.Unmarshal(data, &nctx); err != nil {
+		return Config{}, fmt.Errorf("failed to parse context %q: %w", name, err)
+	}
+	return Config{
+		URL:                nctx.URL,
+		Token:              nctx.Token,
+		User:               nctx.User,
+		Password:           nctx.Password,
+		CredsFile:          expandHome(nctx.Creds),
+		NKeyFile:           expandHome(nctx.NKey),
+		CertFile:           expandHome(nctx.Cert),
+		KeyFile:            expandHome(nctx.Key),
+		CAFile:             expandHome(nctx.CA),
+		JetStreamDomain:    nctx.JetStreamDomain,
+		JetStreamAPIPrefix: nctx.JetStreamAPIPrefix,
+		InboxPrefix:        nctx.InboxPrefix,
+	}, nil
+}
+
+func contextDir() (string, error) {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate the home directory: %w", err)
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "nats", "context"), nil
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,28 @@
+package nats
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var durationDaysRegex = regexp.MustCompile(`([0-9]*\.?[0-9]+)([dw])`)
+
+// ParseDuration parses a Go duration such as 1h30m, also accepting days (d)
+// and weeks (w) units.
+func ParseDuration(s string) (time.Duration, error) {
+	hours := durationDaysRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := durationDaysRegex.FindStringSubmatch(match)
+		value, _ := strconv.ParseFloat(groups[1], 64)
+		if groups[2] == "w" {
+			value *= 7
+		}
+		return strconv.FormatFloat(value*24, 'f', -1, 64) + "h"
+	})
+	d, err := time.ParseDuration(hours)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,464 @@
+package nats
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+)
+
+// OperatorSpec describes the claims of an operator JWT.
+type OperatorSpec struct {
+	Name          string
+	SigningKeys   []string
+	SystemAccount string
+}
+
+// AccountSpec describes the claims of an account JWT.
+type AccountSpec struct {
+	Name        string
+	PublicKey   string
+	SigningKeys []string
+	Limits      AccountLimits
+	// JetStream holds account wide JetStream limits, exclusive with the per
+	// tier limits of JetStreamTiers. JetStream is disabled if both are unset.
+	JetStream         *JetStreamLimits
+	JetStreamTiers    map[string]JetStreamLimits
+	Exports           []Export
+	Imports           []Import
+	ScopedSigningKeys []ScopedSigningKey
+	AuthCallout       *AuthCallout
+	// Revocations revokes the credentials of a user issued before the given
+	// time, "*" revokes all users.
+	Revocations map[string]time.Time
+}
+
+// AccountLimits are the account limits, -1 for unlimited.
+type AccountLimits struct {
+	MaxConnections   int64
+	MaxLeafNodes     int64
+	MaxSubscriptions int64
+	MaxPayload       int64
+	MaxData          int64
+	MaxImports       int64
+	MaxExports       int64
+}
+
+type JetStreamLimits jwt.JetStreamLimits
+
+// Export is a stream or service exported by an account.
+type Export struct {
+	Name          string
+	Subject       string
+	Type          string
+	TokenRequired bool
+	ResponseType  string
+}
+
+// Import is a stream or service imported from another account.
+type Import struct {
+	Name         string
+	Subject      string
+	Account      string
+	Type         string
+	LocalSubject string
+}
+
+// AuthCallout delegates the authentication of the users of an account to
+// the auth callout service connected as one of AuthUsers.
+type AuthCallout struct {
+	AuthUsers []string
+	// AllowedAccounts are the accounts the service can place users in, "*"
+	// for any account.
+	AllowedAccounts []string
+	// XKey is the public curve key the server encrypts the requests to the
+	// service with.
+	XKey string
+}
+
+// UserSpec describes the claims of a user JWT.
+type UserSpec struct {
+	Name string
+	// Account is the public key of the account, needed when the JWT is signed
+	// with one of the account signing keys.
+	Account     string
+	Permissions UserPermissions
+	Tags        []string
+	Expires     time.Time
+	// Scope is the role of the scoped signing key of AccountJWT the user is
+	// issued under. The permissions of the user are those of the scope
+	// template, the user can't set its own.
+	Scope      string
+	AccountJWT string
+}
+
+// UserPermissions are the permissions and limits of a user, or the template
+// of a scoped signing key. Limits are -1 for unlimited.
+type UserPermissions struct {
+	PubAllow         []string
+	PubDeny          []string
+	SubAllow         []string
+	SubDeny          []string
+	Responses        *ResponsePermission
+	ConnectionTypes  []string
+	SourceNetworks   []string
+	TimeRanges       []TimeRange
+	Locale           string
+	BearerToken      bool
+	MaxSubscriptions int64
+	MaxData          int64
+	MaxPayload       int64
+}
+
+// ResponsePermission allows a user to publish replies to the requests it
+// received, without being allowed to publish on the reply subjects otherwise.
+type ResponsePermission struct {
+	MaxMsgs int
+	TTL     time.Duration
+}
+
+// TimeRange is a time of day range, in the 15:04:05 format.
+type TimeRange struct {
+	Start string
+	End   string
+}
+
+// ScopedSigningKey is an account signing key that can only issue users with
+// the permissions of its template.
+type ScopedSigningKey struct {
+	Role     string
+	Key      string
+	Template UserPermissions
+}
+
+var (
+	exportType = map[string]jwt.ExportType{
+		"stream":  jwt.Stream,
+		"service": jwt.Service,
+	}
+	ToExportType = mapFn(exportType)
+)
+
+var (
+	responseType = map[string]jwt.ResponseType{
+		"singleton": jwt.ResponseTypeSingleton,
+		"stream":    jwt.ResponseTypeStream,
+		"chunked":   jwt.ResponseTypeChunked,
+	}
+	ToResponseType = mapFn(responseType)
+)
+
+// IssueOperatorJWT encodes the operator claims, self-signed with the operator
+// seed, and returns the JWT and the operator public key.
+func IssueOperatorJWT(spec OperatorSpec, seed string) (string, string, error) {
+	kp, err := keyPair(seed, nkeys.PrefixByteOperator)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid operator seed: %w", err)
+	}
+	defer kp.Wipe()
+	publicKey, err := kp.PublicKey()
+	if err != nil {
+		return "", "", err
+	}
+	claims := jwt.NewOperatorClaims(publicKey)
+	claims.Name = spec.Name
+	claims.SigningKeys.Add(spec.SigningKeys...)
+	claims.SystemAccount = spec.SystemAccount
+	token, err := encodeClaims(claims, kp)
+	if err != nil {
+		return "", "", err
+	}
+	return token, publicKey, nil
+}
+
+// IssueAccountJWT encodes the account claims signed with the seed of the
+// operator or of one of its signing keys.
+func IssueAccountJWT(spec AccountSpec, signingSeed string) (string, error) {
+	kp, err := keyPair(signingSeed, nkeys.PrefixByteOperator)
+	if err != nil {
+		return "", fmt.Errorf("invalid signing seed: %w", err)
+	}
+	defer kp.Wipe()
+	claims := jwt.NewAccountClaims(spec.PublicKey)
+	if claims == nil {
+		return "", errors.New("account public key is not set")
+	}
+	claims.Name = spec.Name
+	claims.SigningKeys.Add(spec.SigningKeys...)
+	claims.Limits.Conn = spec.Limits.MaxConnections
+	claims.Limits.LeafNodeConn = spec.Limits.MaxLeafNodes
+	claims.Limits.Subs = spec.Limits.MaxSubscriptions
+	claims.Limits.Payload = spec.Limits.MaxPayload
+	claims.Limits.Data = spec.Limits.MaxData
+	claims.Limits.Imports = spec.Limits.MaxImports
+	claims.Limits.Exports = spec.Limits.MaxExports
+	if spec.JetStream != nil {
+		claims.Limits.JetStreamLimits = jwt.JetStreamLimits(*spec.JetStream)
+	}
+	for tier, limits := range spec.JetStreamTiers {
+		claims.Limits.JetStreamTieredLimits[tier] = jwt.JetStreamLimits(limits)
+	}
+	for _, export := range spec.Exports {
+		claims.Exports.Add(&jwt.Export{
+			Name:         export.Name,
+			Subject:      jwt.Subject(export.Subject),
+			Type:         ToExportType(export.Type),
+			TokenReq:     export.TokenRequired,
+			ResponseType: ToResponseType(export.ResponseType),
+		})
+	}
+	for _, imp := range spec.Imports {
+		claims.Imports.Add(&jwt.Import{
+			Name:         imp.Name,
+			Subject:      jwt.Subject(imp.Subject),
+			Account:      imp.Account,
+			Type:         ToExportType(imp.Type),
+			LocalSubject: jwt.RenamingSubject(imp.LocalSubject),
+		})
+	}
+	for _, scope := range spec.ScopedSigningKeys {
+		claims.SigningKeys.AddScopedSigner(&jwt.UserScope{
+			Kind:     jwt.UserScopeType,
+			Key:      scope.Key,
+			Role:     scope.Role,
+			Template: scope.Template.toClaims(),
+		})
+	}
+	if spec.AuthCallout != nil {
+		claims.Authorization.AuthUsers.Add(spec.AuthCallout.AuthUsers...)
+		claims.Authorization.AllowedAccounts.Add(spec.AuthCallout.AllowedAccounts...)
+		claims.Authorization.XKey = spec.AuthCallout.XKey
+	}
+	for publicKey, before := range spec.Revocations {
+		claims.RevokeAt(publicKey, before)
+	}
+	return encodeClaims(claims, kp)
+}
+
+// IssueUserJWT encodes the user claims of the user seed, signed with the seed
+// of the account or of one of its signing keys, and returns the JWT and the
+// user public key.
+func IssueUserJWT(spec UserSpec, seed, signingSeed string) (string, string, error) {
+	user, err := keyPair(seed, nkeys.PrefixByteUser)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid user seed: %w", err)
+	}
+	defer user.Wipe()
+	publicKey, err := user.PublicKey()
+	if err != nil {
+		return "", "", err
+	}
+	kp, err := keyPair(signingSeed, nkeys.PrefixByteAccount)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid signing seed: %w", err)
+	}
+	defer kp.Wipe()
+	signer, err := kp.PublicKey()
+	if err != nil {
+		return "", "", err
+	}
+	claims := jwt.NewUserClaims(publicKey)
+	claims.Name = spec.Name
+	if spec.Account != "" && spec.Account != signer {
+		claims.IssuerAccount = spec.Account
+	}
+	claims.Tags.Add(spec.Tags...)
+	if !spec.Expires.IsZero() {
+		claims.Expires = spec.Expires.Unix()
+	}
+	if spec.Scope == "" {
+		claims.UserPermissionLimits = spec.Permissions.toClaims()
+	} else {
+		// Scoped users must hold no permissions at all, the server applies
+		// the scope template instead.
+		if !reflect.DeepEqual(spec.Permissions.toClaims(), claims.UserPermissionLimits) {
+			return "", "", fmt.Errorf("users issued under scope %q can't set permissions or limits", spec.Scope)
+		}
+		account, err := scopeAccount(spec.AccountJWT, spec.Scope, signer)
+		if err != nil {
+			return "", "", err
+		}
+		claims.IssuerAccount = account
+		claims.UserPermissionLimits = jwt.UserPermissionLimits{}
+	}
+	token, err := encodeClaims(claims, kp)
+	if err != nil {
+		return "", "", err
+	}
+	return token, publicKey, nil
+}
+
+// scopeAccount returns the account public key of the account JWT, checking
+// the signer is the key of the scope with the given role.
+func scopeAccount(accountJWT, role, signer string) (string, error) {
+	account, err := jwt.DecodeAccountClaims(accountJWT)
+	if err != nil {
+		return "", fmt.Errorf("invalid account jwt: %w", err)
+	}
+	for key, scope := range account.SigningKeys {
+		userScope, ok := scope.(*jwt.UserScope)
+		if !ok || userScope.Role != role {
+			continue
+		}
+		if key != signer {
+			return "", fmt.Errorf("the signing seed is not the key of scope %q", role)
+		}
+		return account.Subject, nil
+	}
+	return "", fmt.Errorf("the account has no scoped signing key with role %q", role)
+}
+
+func (p UserPermissions) toClaims() jwt.UserPermissionLimits {
+	var limits jwt.UserPermissionLimits
+	limits.Pub.Allow.Add(p.PubAllow...)
+	limits.Pub.Deny.Add(p.PubDeny...)
+	limits.Sub.Allow.Add(p.SubAllow...)
+	limits.Sub.Deny.Add(p.SubDeny...)
+	if p.Responses != nil {
+		limits.Resp = &jwt.ResponsePermission{MaxMsgs: p.Responses.MaxMsgs, Expires: p.Responses.TTL}
+	}
+	limits.AllowedConnectionTypes.Add(p.ConnectionTypes...)
+	limits.Src = jwt.CIDRList{}
+	limits.Src.Add(p.SourceNetworks...)
+	for _, timeRange := range p.TimeRanges {
+		limits.Times = append(limits.Times, jwt.TimeRange{Start: timeRange.Start, End: timeRange.End})
+	}
+	limits.Locale = p.Locale
+	limits.BearerToken = p.BearerToken
+	limits.Subs = p.MaxSubscriptions
+	limits.Data = p.MaxData
+	limits.Payload = p.MaxPayload
+	return limits
+}
+
+// FormatCreds returns the content of a creds file, holding the user JWT and
+// the user seed.
+func FormatCreds(token, seed string) (string, error) {
+	creds, err := jwt.FormatUserConfig(token, []byte(seed))
+	if err != nil {
+		return "", fmt.Errorf("failed to format creds: %w", err)
+	}
+	return string(creds), nil
+}
+
+var templateRegex = regexp.MustCompile(`{{([^}]*)}}`)
+
+// ValidatePermissionTemplate checks the subject of a scoped signing key
+// template, whose {{...}} functions the server replaces with values of the
+// user or the account when the user connects, e.g. orders.{{tag(team)}}.>
+func ValidatePermissionTemplate(subject string) error {
+	for _, match := range templateRegex.FindAllStringSubmatch(subject, -1) {
+		function := strings.ToLower(strings.TrimSpace(match[1]))
+		switch function {
+		case "name()", "subject()", "account-name()", "account-subject()":
+			continue
+		}
+		name, ok := strings.CutPrefix(function, "tag(")
+		if !ok {
+			name, ok = strings.CutPrefix(function, "account-tag(")
+		}
+		name, closed := strings.CutSuffix(name, ")")
+		if !ok || !closed || strings.TrimSpace(name) == "" || strings.ContainsAny(name, "()") {
+			return fmt.Errorf("unknown template function %q", match[0])
+		}
+	}
+	// Replace the templates to check the rest of the subject
+	rest := templateRegex.ReplaceAllString(subject, "x")
+	if strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("unterminated template in %q", subject)
+	}
+	if !ValidSubject(rest) {
+		return fmt.Errorf("invalid subject %q", subject)
+	}
+	return nil
+}
+
+// AccountPublicKey returns the public key of the account of the account JWT.
+func AccountPublicKey(token string) (string, error) {
+	claims, err := jwt.DecodeAccountClaims(token)
+	if err != nil {
+		return "", fmt.Errorf("invalid account jwt: %w", err)
+	}
+	return claims.Subject, nil
+}
+
+// issueDeleteRequest encodes a request to delete the account JWT from the
+// account resolver, which must be self-signed by the operator or one of its
+// signing keys.
+func issueDeleteRequest(publicKey, operatorSeed string) (string, error) {
+	kp, err := keyPair(operatorSeed, nkeys.PrefixByteOperator)
+	if err != nil {
+		return "", fmt.Errorf("invalid operator seed: %w", err)
+	}
+	defer kp.Wipe()
+	signer, err := kp.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	claims := jwt.NewGenericClaims(signer)
+	claims.Data["accounts"] = []string{publicKey}
+	return encodeClaims(claims, kp)
+}
+
+// SameClaims reports whether the two JWTs hold the same claims, regardless of
+// when they were issued.
+func SameClaims(a, b string) bool {
+	claimsA, errA := decodePayload(a)
+	claimsB, errB := decodePayload(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return reflect.DeepEqual(claimsA, claimsB)
+}
+
+func decodePayload(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid jwt")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	// The issue time and the id, a hash of the claims including the issue
+	// time, change on every encoding.
+	delete(payload, "iat")
+	delete(payload, "jti")
+	return payload, nil
+}
+
+func keyPair(seed string, expected ...nkeys.PrefixByte) (nkeys.KeyPair, error) {
+	kp, err := nkeys.FromSeed([]byte(seed))
+	if err != nil {
+		return nil, err
+	}
+	if err := nkeys.CompatibleKeyPair(kp, expected...); err != nil {
+		kp.Wipe()
+		return nil, err
+	}
+	return kp, nil
+}
+
+func encodeClaims(claims jwt.Claims, kp nkeys.KeyPair) (string, error) {
+	vr := jwt.CreateValidationResults()
+	claims.Validate(vr)
+	if errs := vr.Errors(); len(errs) > 0 {
+		return "", fmt.Errorf("invalid claims: %w", errors.Join(errs...))
+	}
+	token, err := claims.Encode(kp)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign claims: %w", err)
+	}
+	return token, nil
+}
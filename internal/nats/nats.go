@@ -3,42 +3,223 @@ package nats
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/nats-io/nats.go"
 )
 
 type Client interface {
-	GetStream(streamName string) (StreamInfo, error)
+	// GetStream returns the stream info. If subjectsFilter is set, the state
+	// includes the message count of each subject matching it.
+	GetStream(streamName, subjectsFilter string) (StreamInfo, error)
 	CreateStream(streamConfig StreamConfig) (StreamInfo, error)
 	UpdateStream(streamConfig StreamConfig) (StreamInfo, error)
 	DeleteStream(streamName string) error
+	// PurgeStream purges the stream and returns the number of purged messages.
+	PurgeStream(streamName string, opts PurgeOptions) (uint64, error)
+	SnapshotStream(streamName, dir string, opts SnapshotOptions) (Snapshot, error)
+	RestoreStream(streamName, dir string) (StreamInfo, error)
+	ListStreams(subjectFilter string) ([]StreamInfo, error)
+	// ListCachedStreams is like ListStreams without a filter but lists the
+	// streams only once per JetStream domain over the lifetime of the client.
+	ListCachedStreams() ([]StreamInfo, error)
+
+	// GetMessage and GetLastMessage use direct get if the stream allows it.
+	GetMessage(streamName string, sequence uint64) (StreamMessage, error)
+	GetLastMessage(streamName, subject string) (StreamMessage, error)
+	PublishMessage(subject string, data []byte, header map[string][]string, opts PublishOptions) (PubAck, error)
+	DeleteMessage(streamName string, sequence uint64) error
 
 	GetConsumer(streamName, consumerName string) (ConsumerInfo, error)
 	CreateConsumer(streamName string, consumerConfig ConsumerConfig) (ConsumerInfo, error)
 	UpdateConsumer(streamName string, consumerConfig ConsumerConfig) (ConsumerInfo, error)
 	DeleteConsumer(streamName, consumerName string) error
+	// PauseConsumer pauses the consumer until the given deadline, a nil
+	// deadline resumes it.
+	PauseConsumer(streamName, consumerName string, pauseUntil *time.Time) error
+	ListConsumers(streamName string) ([]ConsumerInfo, error)
+
+	GetAccountInfo() (AccountInfo, error)
+	// PushAccountJWT stores the account JWT in the account resolver of the
+	// servers, with the system account credentials.
+	PushAccountJWT(token string) error
+	// GetAccountJWT returns the account JWT stored in the account resolver.
+	GetAccountJWT(publicKey string) (string, error)
+	// DeleteAccountJWT deletes the account JWT from the account resolver,
+	// with a request signed by the operator seed.
+	DeleteAccountJWT(publicKey, operatorSeed string) error
+	// GetCachedAccountInfo is like GetAccountInfo but fetches the account
+	// info only once per JetStream domain over the lifetime of the client.
+	GetCachedAccountInfo() (AccountInfo, error)
+
+	// WithDomain returns a client that targets the given JetStream domain.
+	// An empty domain returns the client as is.
+	WithDomain(domain string) Client
+}
+
+// Config holds the settings used by the client to reach the nats server.
+type Config struct {
+	URL                string
+	Token              string
+	User               string
+	Password           string
+	CredsFile          string
+	NKeyFile           string
+	CertFile           string
+	KeyFile            string
+	CAFile             string
+	InboxPrefix        string
+	JetStreamDomain    string
+	JetStreamAPIPrefix string
+	// SystemCredsFile holds the credentials of a system account user, used
+	// to manage the account JWTs of the resolver.
+	SystemCredsFile string
 }
 
 type client struct {
-	url string
+	config       Config
+	accountInfos *domainCache[AccountInfo]
+	streamLists  *domainCache[[]StreamInfo]
+}
+
+// domainCache holds a value per JetStream domain, shared by the clients
+// returned by WithDomain.
+type domainCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*domainCacheEntry[T]
+}
+
+// domainCacheEntry holds the value of a key, done is closed once it is
+// fetched.
+type domainCacheEntry[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func newDomainCache[T any]() *domainCache[T] {
+	return &domainCache[T]{entries: map[string]*domainCacheEntry[T]{}}
+}
+
+// get returns the value of the key, fetched on the first call only. The fetch
+// runs without holding the cache, so that it doesn't block the other keys,
+// and concurrent calls for the key wait for the same fetch. A failed fetch is
+// not cached, the next call fetches the value again.
+func (d *domainCache[T]) get(key string, fetch func() (T, error)) (T, error) {
+	d.mu.Lock()
+	entry, ok := d.entries[key]
+	if !ok {
+		entry = &domainCacheEntry[T]{done: make(chan struct{})}
+		d.entries[key] = entry
+	}
+	d.mu.Unlock()
+	if ok {
+		<-entry.done
+		return entry.value, entry.err
+	}
+
+	entry.value, entry.err = fetch()
+	if entry.err != nil {
+		d.mu.Lock()
+		delete(d.entries, key)
+		d.mu.Unlock()
+	}
+	close(entry.done)
+	return entry.value, entry.err
 }
 
 // NewClient returns a new nats client.
-func NewClient(url string) Client {
-	return &client{url: url}
+func NewClient(config Config) Client {
+	return &client{config: config, accountInfos: newDomainCache[AccountInfo](), streamLists: newDomainCache[[]StreamInfo]()}
 }
 
-func (c *client) GetStream(streamName string) (StreamInfo, error) {
-	nc, err := nats.Connect(c.url)
+func (c *client) WithDomain(domain string) Client {
+	if domain == "" {
+		return c
+	}
+	config := c.config
+	config.JetStreamDomain = domain
+	config.JetStreamAPIPrefix = ""
+	return &client{config: config, accountInfos: c.accountInfos, streamLists: c.streamLists}
+}
+
+func (c *client) connect() (*nats.Conn, nats.JetStreamContext, error) {
+	nc, err := c.dial()
 	if err != nil {
-		return StreamInfo{}, fmt.Errorf("failed to connect to nats: %w", err)
+		return nil, nil, err
 	}
-	defer nc.Close()
-	js, err := nc.JetStream()
+	var opts []nats.JSOpt
+	switch {
+	case c.config.JetStreamDomain != "":
+		opts = append(opts, nats.Domain(c.config.JetStreamDomain))
+	case c.config.JetStreamAPIPrefix != "":
+		opts = append(opts, nats.APIPrefix(c.config.JetStreamAPIPrefix))
+	}
+	js, err := nc.JetStream(opts...)
 	if err != nil {
-		return StreamInfo{}, fmt.Errorf("failed to create a jetstream context: %w", err)
+		nc.Close()
+		return nil, nil, fmt.Errorf("failed to create a jetstream context: %w", err)
 	}
-	info, err := js.StreamInfo(streamName)
+	return nc, js, nil
+}
+
+func (c *client) dial() (*nats.Conn, error) {
+	connectOpts, err := c.connectOptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	nc, err := nats.Connect(c.config.URL, connectOpts...)
+	if err != nil {
+		if errors.Is(err, nats.ErrAuthorization) || errors.Is(err, nats.ErrAuthExpired) {
+			return nil, fmt.Errorf("failed to connect to nats: %w: %w", ErrUnauthorized, err)
+		}
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	return nc, nil
+}
+
+func (c *client) connectOptions() ([]nats.Option, error) {
+	var opts []nats.Option
+	if c.config.Token != "" {
+		opts = append(opts, nats.Token(c.config.Token))
+	}
+	if c.config.User != "" {
+		opts = append(opts, nats.UserInfo(c.config.User, c.config.Password))
+	}
+	if c.config.CredsFile != "" {
+		opts = append(opts, nats.UserCredentials(c.config.CredsFile))
+	}
+	if c.config.NKeyFile != "" {
+		opt, err := nats.NkeyOptionFromSeed(c.config.NKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load nkey seed: %w", err)
+		}
+		opts = append(opts, opt)
+	}
+	if c.config.CertFile != "" || c.config.KeyFile != "" {
+		opts = append(opts, nats.ClientCert(c.config.CertFile, c.config.KeyFile))
+	}
+	if c.config.CAFile != "" {
+		opts = append(opts, nats.RootCAs(c.config.CAFile))
+	}
+	if c.config.InboxPrefix != "" {
+		opts = append(opts, nats.CustomInboxPrefix(c.config.InboxPrefix))
+	}
+	return opts, nil
+}
+
+func (c *client) GetStream(streamName, subjectsFilter string) (StreamInfo, error) {
+	nc, js, err := c.connect()
+	if err != nil {
+		return StreamInfo{}, err
+	}
+	defer nc.Close()
+	var opts []nats.JSOpt
+	if subjectsFilter != "" {
+		opts = append(opts, &nats.StreamInfoRequest{SubjectsFilter: subjectsFilter})
+	}
+	info, err := js.StreamInfo(streamName, opts...)
 	if err != nil {
 		if errors.Is(err, nats.ErrStreamNotFound) || errors.Is(err, nats.ErrConsumerNotFound) {
 			return StreamInfo{}, ErrNotFound
@@ -49,15 +230,11 @@ func (c *client) GetStream(streamName string) (StreamInfo, error) {
 }
 
 func (c *client) CreateStream(streamConfig StreamConfig) (StreamInfo, error) {
-	nc, err := nats.Connect(c.url)
+	nc, js, err := c.connect()
 	if err != nil {
-		return StreamInfo{}, fmt.Errorf("failed to connect to nats: %w", err)
+		return StreamInfo{}, err
 	}
 	defer nc.Close()
-	js, err := nc.JetStream()
-	if err != nil {
-		return StreamInfo{}, fmt.Errorf("failed to create a jetstream context: %w", err)
-	}
 	cfg := nats.StreamConfig(streamConfig)
 	info, err := js.AddStream(&cfg)
 	if err != nil {
@@ -67,15 +244,11 @@ func (c *client) CreateStream(streamConfig StreamConfig) (StreamInfo, error) {
 }
 
 func (c *client) UpdateStream(streamConfig StreamConfig) (StreamInfo, error) {
-	nc, err := nats.Connect(c.url)
+	nc, js, err := c.connect()
 	if err != nil {
-		return StreamInfo{}, fmt.Errorf("failed to connect to nats: %w", err)
+		return StreamInfo{}, err
 	}
 	defer nc.Close()
-	js, err := nc.JetStream()
-	if err != nil {
-		return StreamInfo{}, fmt.Errorf("failed to create a jetstream context: %w", err)
-	}
 	cfg := nats.StreamConfig(streamConfig)
 	info, err := js.UpdateStream(&cfg)
 	if err != nil {
@@ -85,15 +258,11 @@ func (c *client) UpdateStream(streamConfig StreamConfig) (StreamInfo, error) {
 }
 
 func (c *client) DeleteStream(streamName string) error {
-	nc, err := nats.Connect(c.url)
+	nc, js, err := c.connect()
 	if err != nil {
-		return fmt.Errorf("failed to connect to nats: %w", err)
+		return err
 	}
 	defer nc.Close()
-	js, err := nc.JetStream()
-	if err != nil {
-		return fmt.Errorf("failed to create a jetstream context: %w", err)
-	}
 	err = js.DeleteStream(streamName)
 	if err != nil {
 		return fmt.Errorf("failed to delete stream: %w", err)
@@ -101,75 +270,328 @@ func (c *client) DeleteStream(streamName string) error {
 	return nil
 }
 
-func (c *client) GetConsumer(streamName, consumerName string) (ConsumerInfo, error) {
-	nc, err := nats.Connect(c.url)
+type streamPurgeRequest struct {
+	Subject  string `json:"filter,omitempty"`
+	Sequence uint64 `json:"seq,omitempty"`
+	Keep     uint64 `json:"keep,omitempty"`
+}
+
+type streamPurgeResponse struct {
+	apiResponse
+	Success bool   `json:"success"`
+	Purged  uint64 `json:"purged"`
+}
+
+func (c *client) PurgeStream(streamName string, opts PurgeOptions) (uint64, error) {
+	nc, _, err := c.connect()
+	if err != nil {
+		return 0, err
+	}
+	defer nc.Close()
+	var resp streamPurgeResponse
+	req := streamPurgeRequest(opts)
+	if err := c.apiRequest(nc, "STREAM.PURGE."+streamName, req, &resp); err != nil {
+		return 0, fmt.Errorf("failed to purge stream: %w", err)
+	}
+	if resp.Error != nil {
+		if resp.Error.ErrorCode == int(nats.JSErrCodeStreamNotFound) {
+			return 0, ErrNotFound
+		}
+		return 0, fmt.Errorf("failed to purge stream: %w", resp.Error)
+	}
+	return resp.Purged, nil
+}
+
+func (c *client) GetMessage(streamName string, sequence uint64) (StreamMessage, error) {
+	return c.getMessage(streamName, func(js nats.JetStreamContext, opts ...nats.JSOpt) (*nats.RawStreamMsg, error) {
+		return js.GetMsg(streamName, sequence, opts...)
+	})
+}
+
+func (c *client) GetLastMessage(streamName, subject string) (StreamMessage, error) {
+	return c.getMessage(streamName, func(js nats.JetStreamContext, opts ...nats.JSOpt) (*nats.RawStreamMsg, error) {
+		return js.GetLastMsg(streamName, subject, opts...)
+	})
+}
+
+type getMsgFn func(js nats.JetStreamContext, opts ...nats.JSOpt) (*nats.RawStreamMsg, error)
+
+func (c *client) getMessage(streamName string, get getMsgFn) (StreamMessage, error) {
+	nc, js, err := c.connect()
 	if err != nil {
-		return ConsumerInfo{}, fmt.Errorf("failed to connect to nats: %w", err)
+		return StreamMessage{}, err
 	}
 	defer nc.Close()
-	js, err := nc.JetStream()
+	info, err := js.StreamInfo(streamName)
 	if err != nil {
-		return ConsumerInfo{}, fmt.Errorf("failed to create a jetstream context: %w", err)
+		if errors.Is(err, nats.ErrStreamNotFound) {
+			return StreamMessage{}, ErrNotFound
+		}
+		return StreamMessage{}, fmt.Errorf("failed to retrieve stream info: %w", err)
 	}
-	info, err := js.ConsumerInfo(streamName, consumerName)
+	var opts []nats.JSOpt
+	if info.Config.AllowDirect {
+		opts = append(opts, nats.DirectGet())
+	}
+	msg, err := get(js, opts...)
 	if err != nil {
-		if errors.Is(err, nats.ErrStreamNotFound) || errors.Is(err, nats.ErrConsumerNotFound) {
-			return ConsumerInfo{}, ErrNotFound
+		if errors.Is(err, nats.ErrMsgNotFound) {
+			return StreamMessage{}, ErrNotFound
 		}
-		return ConsumerInfo{}, fmt.Errorf("failed to retrieve consumer info: %w", err)
+		return StreamMessage{}, fmt.Errorf("failed to get message: %w", err)
 	}
-	return ConsumerInfo(*info), nil
+	return StreamMessage(*msg), nil
 }
 
-func (c *client) CreateConsumer(streamName string, consumerConfig ConsumerConfig) (ConsumerInfo, error) {
-	nc, err := nats.Connect(c.url)
+func (c *client) PublishMessage(subject string, data []byte, header map[string][]string, opts PublishOptions) (PubAck, error) {
+	nc, js, err := c.connect()
 	if err != nil {
-		return ConsumerInfo{}, fmt.Errorf("failed to connect to nats: %w", err)
+		return PubAck{}, err
 	}
 	defer nc.Close()
-	js, err := nc.JetStream()
+	var pubOpts []nats.PubOpt
+	if opts.MsgID != "" {
+		pubOpts = append(pubOpts, nats.MsgId(opts.MsgID))
+	}
+	if opts.ExpectedStream != "" {
+		pubOpts = append(pubOpts, nats.ExpectStream(opts.ExpectedStream))
+	}
+	if opts.ExpectedLastSequence != nil {
+		pubOpts = append(pubOpts, nats.ExpectLastSequence(*opts.ExpectedLastSequence))
+	}
+	msg := &nats.Msg{Subject: subject, Data: data, Header: header}
+	ack, err := js.PublishMsg(msg, pubOpts...)
 	if err != nil {
-		return ConsumerInfo{}, fmt.Errorf("failed to create a jetstream context: %w", err)
+		return PubAck{}, fmt.Errorf("failed to publish message: %w", err)
 	}
-	cfg := nats.ConsumerConfig(consumerConfig)
-	info, err := js.AddConsumer(streamName, &cfg)
+	return PubAck(*ack), nil
+}
+
+func (c *client) DeleteMessage(streamName string, sequence uint64) error {
+	nc, js, err := c.connect()
 	if err != nil {
-		return ConsumerInfo{}, fmt.Errorf("failed to create consumer: %w", err)
+		return err
+	}
+	defer nc.Close()
+	err = js.DeleteMsg(streamName, sequence)
+	if err != nil {
+		if errors.Is(err, nats.ErrMsgNotFound) || errors.Is(err, nats.ErrStreamNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete message: %w", err)
 	}
-	return ConsumerInfo(*info), nil
+	return nil
 }
 
-func (c *client) UpdateConsumer(streamName string, consumerConfig ConsumerConfig) (ConsumerInfo, error) {
-	nc, err := nats.Connect(c.url)
+type streamListRequest struct {
+	Offset  int    `json:"offset"`
+	Subject string `json:"subject,omitempty"`
+}
+
+type streamListResponse struct {
+	apiResponse
+	apiPaged
+	Streams []*nats.StreamInfo `json:"streams"`
+}
+
+// The stream list is requested directly, the stream lister of the jetstream
+// context drops the errors of the requests.
+func (c *client) ListStreams(subjectFilter string) ([]StreamInfo, error) {
+	nc, _, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer nc.Close()
+	var streams []StreamInfo
+	for {
+		var resp streamListResponse
+		req := streamListRequest{Offset: len(streams), Subject: subjectFilter}
+		if err := c.apiRequest(nc, "STREAM.LIST", req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list streams: %w", err)
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("failed to list streams: %w", resp.Error)
+		}
+		for _, info := range resp.Streams {
+			streams = append(streams, StreamInfo(*info))
+		}
+		if len(resp.Streams) == 0 || len(streams) >= resp.Total {
+			return streams, nil
+		}
+	}
+}
+
+func (c *client) ListCachedStreams() ([]StreamInfo, error) {
+	return c.streamLists.get(c.apiSubject(""), func() ([]StreamInfo, error) {
+		return c.ListStreams("")
+	})
+}
+
+type consumerCreateRequest struct {
+	Stream string         `json:"stream_name"`
+	Config ConsumerConfig `json:"config"`
+	Action string         `json:"action,omitempty"`
+}
+
+type consumerResponse struct {
+	apiResponse
+	*ConsumerInfo
+}
+
+// The consumer endpoints are requested directly, the jetstream context would
+// drop the settings and state its types don't know about.
+func (c *client) GetConsumer(streamName, consumerName string) (ConsumerInfo, error) {
+	nc, _, err := c.connect()
 	if err != nil {
-		return ConsumerInfo{}, fmt.Errorf("failed to connect to nats: %w", err)
+		return ConsumerInfo{}, err
 	}
 	defer nc.Close()
-	js, err := nc.JetStream()
+	var resp consumerResponse
+	if err := c.apiRequest(nc, "CONSUMER.INFO."+streamName+"."+consumerName, nil, &resp); err != nil {
+		return ConsumerInfo{}, fmt.Errorf("failed to retrieve consumer info: %w", err)
+	}
+	if resp.Error != nil {
+		if resp.Error.ErrorCode == int(nats.JSErrCodeStreamNotFound) || resp.Error.ErrorCode == int(nats.JSErrCodeConsumerNotFound) {
+			return ConsumerInfo{}, ErrNotFound
+		}
+		return ConsumerInfo{}, fmt.Errorf("failed to retrieve consumer info: %w", resp.Error)
+	}
+	if resp.ConsumerInfo == nil {
+		return ConsumerInfo{}, errors.New("failed to retrieve consumer info: empty response")
+	}
+	return *resp.ConsumerInfo, nil
+}
+
+func (c *client) CreateConsumer(streamName string, consumerConfig ConsumerConfig) (ConsumerInfo, error) {
+	info, err := c.upsertConsumer(streamName, consumerConfig, "create")
 	if err != nil {
-		return ConsumerInfo{}, fmt.Errorf("failed to create a jetstream context: %w", err)
+		return ConsumerInfo{}, fmt.Errorf("failed to create consumer: %w", err)
 	}
-	cfg := nats.ConsumerConfig(consumerConfig)
-	info, err := js.UpdateConsumer(streamName, &cfg)
+	return info, nil
+}
+
+func (c *client) UpdateConsumer(streamName string, consumerConfig ConsumerConfig) (ConsumerInfo, error) {
+	info, err := c.upsertConsumer(streamName, consumerConfig, "update")
 	if err != nil {
 		return ConsumerInfo{}, fmt.Errorf("failed to update consumer: %w", err)
 	}
-	return ConsumerInfo(*info), nil
+	return info, nil
 }
 
-func (c *client) DeleteConsumer(streamName, consumerName string) error {
-	nc, err := nats.Connect(c.url)
+func (c *client) upsertConsumer(streamName string, consumerConfig ConsumerConfig, action string) (ConsumerInfo, error) {
+	nc, _, err := c.connect()
+	if err != nil {
+		return ConsumerInfo{}, err
+	}
+	defer nc.Close()
+	var resp consumerResponse
+	req := consumerCreateRequest{Stream: streamName, Config: consumerConfig, Action: action}
+	if err := c.apiRequest(nc, "CONSUMER.CREATE."+streamName+"."+consumerConfig.Name, req, &resp); err != nil {
+		return ConsumerInfo{}, err
+	}
+	if resp.Error != nil {
+		return ConsumerInfo{}, resp.Error
+	}
+	if resp.ConsumerInfo == nil {
+		return ConsumerInfo{}, errors.New("empty response")
+	}
+	return *resp.ConsumerInfo, nil
+}
+
+type consumerPauseRequest struct {
+	PauseUntil *time.Time `json:"pause_until,omitempty"`
+}
+
+func (c *client) PauseConsumer(streamName, consumerName string, pauseUntil *time.Time) error {
+	nc, _, err := c.connect()
 	if err != nil {
-		return fmt.Errorf("failed to connect to nats: %w", err)
+		return err
 	}
 	defer nc.Close()
-	js, err := nc.JetStream()
+	var resp apiResponse
+	req := consumerPauseRequest{PauseUntil: pauseUntil}
+	if err := c.apiRequest(nc, "CONSUMER.PAUSE."+streamName+"."+consumerName, req, &resp); err != nil {
+		return fmt.Errorf("failed to pause consumer: %w", err)
+	}
+	if resp.Error != nil {
+		if resp.Error.ErrorCode == int(nats.JSErrCodeStreamNotFound) || resp.Error.ErrorCode == int(nats.JSErrCodeConsumerNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to pause consumer: %w", resp.Error)
+	}
+	return nil
+}
+
+func (c *client) DeleteConsumer(streamName, consumerName string) error {
+	nc, js, err := c.connect()
 	if err != nil {
-		return fmt.Errorf("failed to create a jetstream context: %w", err)
+		return err
 	}
+	defer nc.Close()
 	err = js.DeleteConsumer(streamName, consumerName)
 	if err != nil {
+		if errors.Is(err, nats.ErrStreamNotFound) || errors.Is(err, nats.ErrConsumerNotFound) {
+			return ErrNotFound
+		}
 		return fmt.Errorf("failed to delete consumer: %w", err)
 	}
 	return nil
 }
+
+type consumerListRequest struct {
+	Offset int `json:"offset"`
+}
+
+type consumerListResponse struct {
+	apiResponse
+	apiPaged
+	Consumers []*ConsumerInfo `json:"consumers"`
+}
+
+func (c *client) ListConsumers(streamName string) ([]ConsumerInfo, error) {
+	nc, _, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer nc.Close()
+	var consumers []ConsumerInfo
+	for {
+		var resp consumerListResponse
+		req := consumerListRequest{Offset: len(consumers)}
+		if err := c.apiRequest(nc, "CONSUMER.LIST."+streamName, req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list consumers: %w", err)
+		}
+		if resp.Error != nil {
+			if resp.Error.ErrorCode == int(nats.JSErrCodeStreamNotFound) {
+				return nil, ErrNotFound
+			}
+			return nil, fmt.Errorf("failed to list consumers: %w", resp.Error)
+		}
+		for _, info := range resp.Consumers {
+			consumers = append(consumers, *info)
+		}
+		if len(resp.Consumers) == 0 || len(consumers) >= resp.Total {
+			return consumers, nil
+		}
+	}
+}
+
+func (c *client) GetAccountInfo() (AccountInfo, error) {
+	nc, js, err := c.connect()
+	if err != nil {
+		return AccountInfo{}, err
+	}
+	defer nc.Close()
+	info, err := js.AccountInfo()
+	if err != nil {
+		if errors.Is(err, nats.ErrJetStreamNotEnabled) || errors.Is(err, nats.ErrJetStreamNotEnabledForAccount) {
+			return AccountInfo{}, fmt.Errorf("failed to retrieve account info: %w: %w", ErrJetStreamNotEnabled, err)
+		}
+		return AccountInfo{}, fmt.Errorf("failed to retrieve account info: %w", err)
+	}
+	return AccountInfo(*info), nil
+}
+
+func (c *client) GetCachedAccountInfo() (AccountInfo, error) {
+	return c.accountInfos.get(c.apiSubject(""), c.GetAccountInfo)
+}
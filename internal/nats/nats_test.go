@@ -3,15 +3,19 @@ package nats
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/nats-io/jwt/v2"
 	"github.com/stretchr/testify/require"
 )
 
 func Test__GetStream(t *testing.T) {
 	t.Skip()
 	c := makeTestClient()
-	info, err := c.GetStream("orders")
+	info, err := c.GetStream("orders", "")
 	require.NoError(t, err)
 	data, err := json.Marshal(info)
 	require.NoError(t, err)
@@ -28,6 +32,425 @@ func Test__GetConsumer(t *testing.T) {
 	fmt.Println(string(data))
 }
 
+func Test__LoadContext(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	contextDir := filepath.Join(configDir, "nats", "context")
+	require.NoError(t, os.MkdirAll(contextDir, 0o755))
+	data := `{"url": "nats://hub:4222", "creds": "/secrets/orders.creds", "jetstream_domain": "hub", "inbox_prefix": "_INBOX_orders"}`
+	require.NoError(t, os.WriteFile(filepath.Join(contextDir, "orders.json"), []byte(data), 0o600))
+
+	config, err := LoadContext("orders")
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		URL:             "nats://hub:4222",
+		CredsFile:       "/secrets/orders.creds",
+		JetStreamDomain: "hub",
+		InboxPrefix:     "_INBOX_orders",
+	}, config)
+
+	_, err = LoadContext("missing")
+	require.Error(t, err)
+	_, err = LoadContext("../orders")
+	require.Error(t, err)
+}
+
+func Test__ReadSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	_, err := ReadSnapshot(dir)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	data := []byte("snapshot data")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, snapshotDataFile), data, 0o600))
+	metadata := `{"config": {"name": "orders"}, "checksum": "2b4c2ccd8bb8ec8e4b2c8a6a0a4f7c2e3c6a9b0c2b5e0d0f2c7c2a3e8f1b7d6a", "size": 13}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, snapshotMetadataFile), []byte(metadata), 0o600))
+	_, err = ReadSnapshot(dir)
+	require.ErrorContains(t, err, "checksum mismatch")
+
+	metadata = `{"config": {"name": "orders"}, "checksum": "e7dee7266896538616b630a5da40a90e007726a383e005a9c9c5dd0c2daf9329", "size": 13}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, snapshotMetadataFile), []byte(metadata), 0o600))
+	snapshot, err := ReadSnapshot(dir)
+	require.NoError(t, err)
+	require.Equal(t, "orders", snapshot.Config.Name)
+	require.Equal(t, int64(13), snapshot.Size)
+}
+
+func Test__ConsumerInfo(t *testing.T) {
+	data := `{"stream_name": "orders", "name": "new_order_consumer", "config": {"durable_name": "new_order_consumer", "ack_policy": "explicit", "pause_until": "2030-01-02T03:04:05Z"}, "num_pending": 3, "paused": true, "pause_remaining": 1000000000}`
+	var info ConsumerInfo
+	require.NoError(t, json.Unmarshal([]byte(data), &info))
+	require.Equal(t, "new_order_consumer", info.Config.Durable)
+	require.Equal(t, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), *info.Config.PauseUntil)
+	require.Equal(t, uint64(3), info.NumPending)
+	require.True(t, info.Paused)
+	require.Equal(t, time.Second, info.PauseRemaining)
+}
+
+func Test__ConsumerFilters(t *testing.T) {
+	var cfg ConsumerConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"filter_subject": "orders.created"}`), &cfg))
+	require.Equal(t, []string{"orders.created"}, cfg.Filters())
+	require.NoError(t, json.Unmarshal([]byte(`{"filter_subjects": ["orders.created", "orders.paid"]}`), &cfg))
+	require.Equal(t, []string{"orders.created", "orders.paid"}, cfg.Filters())
+	require.Empty(t, ConsumerConfig{}.Filters())
+}
+
+func Test__PriorityPolicy(t *testing.T) {
+	data, err := json.Marshal(ConsumerConfig{PriorityGroups: []string{"jobs"}, PriorityPolicy: PriorityPinnedClient})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"priority_groups":["jobs"],"priority_policy":"pinned_client"`)
+
+	data, err = json.Marshal(ConsumerConfig{})
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "priority_policy")
+
+	var cfg ConsumerConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"priority_policy": "overflow", "priority_timeout": 120000000000}`), &cfg))
+	require.Equal(t, PriorityOverflow, cfg.PriorityPolicy)
+	require.Equal(t, 2*time.Minute, cfg.PriorityTimeout)
+	require.NoError(t, json.Unmarshal([]byte(`{"priority_policy": "prioritized"}`), &cfg))
+	require.Equal(t, PriorityUnknown, cfg.PriorityPolicy)
+	require.Equal(t, "unknown", FromPriorityPolicy(cfg.PriorityPolicy))
+	_, err = json.Marshal(cfg)
+	require.Error(t, err)
+}
+
+func Test__NKey(t *testing.T) {
+	for _, keyType := range []string{"operator", "account", "user", "server", "curve"} {
+		key, err := CreateNKey(keyType)
+		require.NoError(t, err)
+		require.Equal(t, keyType, key.Type)
+
+		parsed, err := ParseNKeySeed(key.Seed)
+		require.NoError(t, err)
+		require.Equal(t, key, parsed)
+	}
+
+	_, err := CreateNKey("cluster")
+	require.Error(t, err)
+	_, err = ParseNKeySeed("SUnotaseed")
+	require.Error(t, err)
+}
+
+func Test__IssueJWT(t *testing.T) {
+	operator, err := CreateNKey("operator")
+	require.NoError(t, err)
+	account, err := CreateNKey("account")
+	require.NoError(t, err)
+
+	operatorJWT, publicKey, err := IssueOperatorJWT(OperatorSpec{Name: "acme", SystemAccount: account.PublicKey}, operator.Seed)
+	require.NoError(t, err)
+	require.Equal(t, operator.PublicKey, publicKey)
+	_, _, err = IssueOperatorJWT(OperatorSpec{Name: "acme"}, account.Seed)
+	require.Error(t, err)
+
+	spec := AccountSpec{
+		Name:      "orders",
+		PublicKey: account.PublicKey,
+		Limits:    AccountLimits{MaxConnections: 10, MaxLeafNodes: -1, MaxSubscriptions: -1, MaxPayload: -1, MaxData: -1, MaxImports: -1, MaxExports: -1},
+		JetStream: &JetStreamLimits{MemoryStorage: -1, DiskStorage: 1 << 30, Streams: -1, Consumer: -1},
+		Exports:   []Export{{Name: "orders", Subject: "orders.>", Type: "stream"}},
+	}
+	accountJWT, err := IssueAccountJWT(spec, operator.Seed)
+	require.NoError(t, err)
+	time.Sleep(time.Second)
+	reissued, err := IssueAccountJWT(spec, operator.Seed)
+	require.NoError(t, err)
+	require.NotEqual(t, accountJWT, reissued)
+	require.True(t, SameClaims(accountJWT, reissued))
+	require.False(t, SameClaims(accountJWT, operatorJWT))
+
+	spec.Limits.MaxConnections = 20
+	changed, err := IssueAccountJWT(spec, operator.Seed)
+	require.NoError(t, err)
+	require.False(t, SameClaims(accountJWT, changed))
+
+	spec.JetStreamTiers = map[string]JetStreamLimits{"R1": {DiskStorage: 1 << 30}}
+	_, err = IssueAccountJWT(spec, operator.Seed)
+	require.ErrorContains(t, err, "mutually exclusive")
+}
+
+func Test__IssueUserJWT(t *testing.T) {
+	account, err := CreateNKey("account")
+	require.NoError(t, err)
+	signingKey, err := CreateNKey("account")
+	require.NoError(t, err)
+	user, err := CreateNKey("user")
+	require.NoError(t, err)
+
+	spec := UserSpec{
+		Name:    "orders-service",
+		Account: account.PublicKey,
+		Permissions: UserPermissions{
+			PubAllow:         []string{"orders.>"},
+			SubAllow:         []string{"_INBOX.>"},
+			Responses:        &ResponsePermission{MaxMsgs: 1, TTL: time.Minute},
+			ConnectionTypes:  []string{"STANDARD"},
+			SourceNetworks:   []string{"10.0.0.0/8"},
+			TimeRanges:       []TimeRange{{Start: "08:00:00", End: "18:00:00"}},
+			Locale:           "UTC",
+			MaxSubscriptions: -1,
+			MaxData:          -1,
+			MaxPayload:       -1,
+		},
+	}
+	token, publicKey, err := IssueUserJWT(spec, user.Seed, signingKey.Seed)
+	require.NoError(t, err)
+	require.Equal(t, user.PublicKey, publicKey)
+	claims, err := jwt.DecodeUserClaims(token)
+	require.NoError(t, err)
+	require.Equal(t, account.PublicKey, claims.IssuerAccount)
+	require.Equal(t, signingKey.PublicKey, claims.Issuer)
+
+	creds, err := FormatCreds(token, user.Seed)
+	require.NoError(t, err)
+	require.Contains(t, creds, "-----BEGIN NATS USER JWT-----\n"+token)
+	require.Contains(t, creds, user.Seed)
+
+	_, _, err = IssueUserJWT(spec, account.Seed, signingKey.Seed)
+	require.ErrorContains(t, err, "invalid user seed")
+	spec.Permissions.SourceNetworks = []string{"10.0.0.0/33"}
+	_, _, err = IssueUserJWT(spec, user.Seed, signingKey.Seed)
+	require.Error(t, err)
+}
+
+func Test__ScopedSigningKeys(t *testing.T) {
+	operator, err := CreateNKey("operator")
+	require.NoError(t, err)
+	account, err := CreateNKey("account")
+	require.NoError(t, err)
+	scopeKey, err := CreateNKey("account")
+	require.NoError(t, err)
+	user, err := CreateNKey("user")
+	require.NoError(t, err)
+
+	accountJWT, err := IssueAccountJWT(AccountSpec{
+		Name:      "apps",
+		PublicKey: account.PublicKey,
+		ScopedSigningKeys: []ScopedSigningKey{{
+			Role: "app-team",
+			Key:  scopeKey.PublicKey,
+			Template: UserPermissions{
+				PubAllow:         []string{"apps.{{tag(team)}}.>"},
+				MaxSubscriptions: 10,
+				MaxData:          -1,
+				MaxPayload:       -1,
+			},
+		}},
+	}, operator.Seed)
+	require.NoError(t, err)
+	accountClaims, err := jwt.DecodeAccountClaims(accountJWT)
+	require.NoError(t, err)
+	scope, ok := accountClaims.SigningKeys.GetScope(scopeKey.PublicKey)
+	require.True(t, ok)
+	require.Equal(t, "app-team", scope.(*jwt.UserScope).Role)
+
+	unlimited := UserPermissions{MaxSubscriptions: -1, MaxData: -1, MaxPayload: -1}
+	spec := UserSpec{Name: "billing", Tags: []string{"team:billing"}, Permissions: unlimited, Scope: "app-team", AccountJWT: accountJWT}
+	token, _, err := IssueUserJWT(spec, user.Seed, scopeKey.Seed)
+	require.NoError(t, err)
+	claims, err := jwt.DecodeUserClaims(token)
+	require.NoError(t, err)
+	require.Equal(t, account.PublicKey, claims.IssuerAccount)
+	require.True(t, claims.HasEmptyPermissions())
+	require.NoError(t, scope.ValidateScopedSigner(claims))
+
+	_, _, err = IssueUserJWT(spec, user.Seed, account.Seed)
+	require.ErrorContains(t, err, "not the key of scope")
+	spec.Scope = "ops-team"
+	_, _, err = IssueUserJWT(spec, user.Seed, scopeKey.Seed)
+	require.ErrorContains(t, err, "no scoped signing key")
+	spec.Scope = "app-team"
+	spec.Permissions.PubAllow = []string{"apps.>"}
+	_, _, err = IssueUserJWT(spec, user.Seed, scopeKey.Seed)
+	require.ErrorContains(t, err, "can't set permissions")
+}
+
+func Test__ValidatePermissionTemplate(t *testing.T) {
+	for _, subject := range []string{"apps.>", "apps.{{name()}}.>", "apps.{{ tag(team) }}.{{account-tag(env)}}", "_INBOX.{{subject()}}.*", "{{account-name()}}.{{account-subject()}}"} {
+		require.NoError(t, ValidatePermissionTemplate(subject), subject)
+	}
+	for _, subject := range []string{"apps.{{nam()}}", "apps.{{tag()}}", "apps.{{tag(team}}", "apps.{{name()}", "apps..{{name()}}", "apps.>.{{name()}}"} {
+		require.Error(t, ValidatePermissionTemplate(subject), subject)
+	}
+}
+
+func Test__AuthCallout(t *testing.T) {
+	operator, err := CreateNKey("operator")
+	require.NoError(t, err)
+	account, err := CreateNKey("account")
+	require.NoError(t, err)
+	user, err := CreateNKey("user")
+	require.NoError(t, err)
+	xkey, err := CreateNKey("curve")
+	require.NoError(t, err)
+	require.True(t, ValidPublicKey(user.PublicKey, "user"))
+	require.False(t, ValidPublicKey(user.PublicKey, "account"))
+	require.False(t, ValidPublicKey(user.Seed, "user"))
+
+	spec := AccountSpec{
+		Name:      "auth",
+		PublicKey: account.PublicKey,
+		AuthCallout: &AuthCallout{
+			AuthUsers:       []string{user.PublicKey},
+			AllowedAccounts: []string{"*"},
+			XKey:            xkey.PublicKey,
+		},
+	}
+	token, err := IssueAccountJWT(spec, operator.Seed)
+	require.NoError(t, err)
+	claims, err := jwt.DecodeAccountClaims(token)
+	require.NoError(t, err)
+	require.True(t, claims.HasExternalAuthorization())
+	require.Equal(t, jwt.StringList{"*"}, claims.Authorization.AllowedAccounts)
+	require.Equal(t, xkey.PublicKey, claims.Authorization.XKey)
+
+	spec.AuthCallout.AuthUsers = []string{account.PublicKey}
+	_, err = IssueAccountJWT(spec, operator.Seed)
+	require.Error(t, err)
+}
+
+func Test__AccountJWTRequests(t *testing.T) {
+	operator, err := CreateNKey("operator")
+	require.NoError(t, err)
+	account, err := CreateNKey("account")
+	require.NoError(t, err)
+
+	token, err := IssueAccountJWT(AccountSpec{Name: "orders", PublicKey: account.PublicKey}, operator.Seed)
+	require.NoError(t, err)
+	publicKey, err := AccountPublicKey(token)
+	require.NoError(t, err)
+	require.Equal(t, account.PublicKey, publicKey)
+
+	// The resolver only accepts delete requests self-signed by the operator
+	request, err := issueDeleteRequest(account.PublicKey, operator.Seed)
+	require.NoError(t, err)
+	claims, err := jwt.DecodeGeneric(request)
+	require.NoError(t, err)
+	require.Equal(t, operator.PublicKey, claims.Subject)
+	require.Equal(t, operator.PublicKey, claims.Issuer)
+	require.Equal(t, []any{account.PublicKey}, claims.Data["accounts"])
+
+	_, err = issueDeleteRequest(account.PublicKey, account.Seed)
+	require.ErrorContains(t, err, "invalid operator seed")
+	_, err = makeTestClient().GetAccountJWT(account.PublicKey)
+	require.ErrorIs(t, err, ErrNoSystemCreds)
+}
+
 func makeTestClient() *client {
-	return &client{url: "nats://localhost:4222"}
+	return &client{config: Config{URL: "nats://localhost:4222"}}
+}
+
+func Test__ServerConfig(t *testing.T) {
+	config := ServerConfig{
+		ServerName: "n1",
+		Listen:     "0.0.0.0:4222",
+		TLS:        &ServerTLS{CertFile: "/etc/nats/cert.pem", KeyFile: "/etc/nats/key.pem"},
+		JetStream:  &ServerJetStream{StoreDir: "/data/jetstream", MaxFileStore: 1 << 30},
+		Cluster:    &ServerCluster{Name: "c1", Listen: "0.0.0.0:6222", Routes: []string{"nats://n2:6222"}},
+		Accounts: []ServerAccount{{
+			Name:      "$APP",
+			JetStream: true,
+			Users:     []ServerUser{{User: "app", Password: `p"w`, Permissions: &ServerPermissions{PubAllow: []string{"orders.>"}}}},
+		}},
+	}
+	rendered := RenderServerConfig(config)
+	require.Contains(t, rendered, "\n  \"$APP\" {\n")
+	require.Contains(t, rendered, `password: "p\"w"`)
+	require.Contains(t, rendered, `routes: ["nats://n2:6222"]`)
+	// The TLS files don't exist here
+	warnings, err := ValidateServerConfig(config)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	config.TLS.CertFile = ""
+	config.Accounts[0].Users[0].Permissions.PubAllow = []string{"orders..>"}
+	_, err = ValidateServerConfig(config)
+	require.ErrorContains(t, err, "missing 'cert_file'")
+	require.ErrorContains(t, err, `subject "orders..>" is not a valid subject`)
+	require.NotContains(t, err.Error(), os.TempDir())
+}
+
+func Test__Subjects(t *testing.T) {
+	for _, c := range []struct {
+		subject, pattern string
+		matches          bool
+	}{
+		{"orders.new", "orders.new", true},
+		{"orders.new", "orders.*", true},
+		{"orders.new.eu", "orders.>", true},
+		{"orders.*", "orders.>", true},
+		{"orders.>", "orders.*", false},
+		{"orders", "orders.>", false},
+		{"orders.new.eu", "orders.*", false},
+		{"orders.*", "orders.new", false},
+		{"orders.new", "*.*", true},
+	} {
+		require.Equal(t, c.matches, SubjectMatches(c.subject, c.pattern), "%s ⊆ %s", c.subject, c.pattern)
+	}
+	for _, c := range []struct {
+		a, b     string
+		overlaps bool
+	}{
+		{"orders.new", "orders.new", true},
+		{"orders.*", "*.new", true},
+		{"orders.>", "orders.new.eu", true},
+		{"orders.>", "orders", false},
+		{"orders.*", "orders.new.eu", false},
+		{"orders.new", "orders.old", false},
+		{">", "anything.at.all", true},
+	} {
+		require.Equal(t, c.overlaps, SubjectsOverlap(c.a, c.b), "%s ∩ %s", c.a, c.b)
+		require.Equal(t, c.overlaps, SubjectsOverlap(c.b, c.a), "%s ∩ %s", c.b, c.a)
+	}
+}
+
+func Test__ParseDuration(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"90s":    90 * time.Second,
+		"1h30m":  90 * time.Minute,
+		"7d":     7 * 24 * time.Hour,
+		"1w1d":   8 * 24 * time.Hour,
+		"1.5d2h": 38 * time.Hour,
+		"100ms":  100 * time.Millisecond,
+	} {
+		d, err := ParseDuration(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, d, s)
+	}
+	for _, s := range []string{"", "7", "1x", "d"} {
+		_, err := ParseDuration(s)
+		require.ErrorContains(t, err, "invalid duration", s)
+	}
+}
+
+func Test__DomainCache(t *testing.T) {
+	cache := newDomainCache[int]()
+	release := make(chan struct{})
+	fetching := make(chan struct{})
+	go func() {
+		_, _ = cache.get("slow", func() (int, error) {
+			close(fetching)
+			<-release
+			return 1, nil
+		})
+	}()
+	<-fetching
+
+	// Fetching a key doesn't block the other keys
+	value, err := cache.get("fast", func() (int, error) { return 2, nil })
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+
+	close(release)
+	value, err = cache.get("slow", func() (int, error) { return 0, fmt.Errorf("fetched twice") })
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	// Failed fetches are not cached
+	_, err = cache.get("failing", func() (int, error) { return 0, fmt.Errorf("unreachable") })
+	require.Error(t, err)
+	value, err = cache.get("failing", func() (int, error) { return 3, nil })
+	require.NoError(t, err)
+	require.Equal(t, 3, value)
 }
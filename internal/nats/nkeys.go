@@ -0,0 +1,80 @@
+package nats
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nkeys"
+)
+
+var (
+	nkeyType = map[string]nkeys.PrefixByte{
+		"operator": nkeys.PrefixByteOperator,
+		"account":  nkeys.PrefixByteAccount,
+		"user":     nkeys.PrefixByteUser,
+		"server":   nkeys.PrefixByteServer,
+		"curve":    nkeys.PrefixByteCurve,
+	}
+	invertedNKeyType = invertMap(nkeyType)
+)
+
+// NKey is an nkey pair, as its public key and seed.
+type NKey struct {
+	Type      string
+	PublicKey string
+	Seed      string
+}
+
+// CreateNKey generates a key pair of the given type, one of operator,
+// account, user, server and curve.
+func CreateNKey(keyType string) (NKey, error) {
+	prefix, ok := nkeyType[keyType]
+	if !ok {
+		return NKey{}, fmt.Errorf("unknown nkey type: %s", keyType)
+	}
+	kp, err := nkeys.CreatePair(prefix)
+	if err != nil {
+		return NKey{}, fmt.Errorf("failed to create nkey: %w", err)
+	}
+	defer kp.Wipe()
+	return toNKey(keyType, kp)
+}
+
+// ParseNKeySeed returns the key pair of the given seed.
+func ParseNKeySeed(seed string) (NKey, error) {
+	prefix, _, err := nkeys.DecodeSeed([]byte(seed))
+	if err != nil {
+		return NKey{}, fmt.Errorf("invalid nkey seed: %w", err)
+	}
+	keyType, ok := invertedNKeyType[prefix]
+	if !ok {
+		return NKey{}, fmt.Errorf("unsupported nkey type: %s", prefix)
+	}
+	kp, err := nkeys.FromSeed([]byte(seed))
+	if err != nil {
+		return NKey{}, fmt.Errorf("invalid nkey seed: %w", err)
+	}
+	defer kp.Wipe()
+	return toNKey(keyType, kp)
+}
+
+// ValidPublicKey reports whether publicKey is a public key of the given type.
+func ValidPublicKey(publicKey, keyType string) bool {
+	prefix, ok := nkeyType[keyType]
+	if !ok {
+		return false
+	}
+	_, err := nkeys.Decode(prefix, []byte(publicKey))
+	return err == nil
+}
+
+func toNKey(keyType string, kp nkeys.KeyPair) (NKey, error) {
+	publicKey, err := kp.PublicKey()
+	if err != nil {
+		return NKey{}, err
+	}
+	seed, err := kp.Seed()
+	if err != nil {
+		return NKey{}, err
+	}
+	return NKey{Type: keyType, PublicKey: publicKey, Seed: string(seed)}, nil
+}
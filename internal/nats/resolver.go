@@ -0,0 +1,105 @@
+package nats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	claimsUpdateSubject = "$SYS.REQ.CLAIMS.UPDATE"
+	claimsDeleteSubject = "$SYS.REQ.CLAIMS.DELETE"
+	claimsLookupSubject = "$SYS.REQ.ACCOUNT.%s.CLAIMS.LOOKUP"
+)
+
+// claimsError is the error body returned by the account resolver.
+type claimsError struct {
+	Account     string `json:"account,omitempty"`
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+func (e *claimsError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Description, e.Code)
+}
+
+type claimsResponse struct {
+	Error *claimsError `json:"error,omitempty"`
+}
+
+func (c *client) PushAccountJWT(token string) error {
+	if err := c.claimsRequest(claimsUpdateSubject, token); err != nil {
+		return fmt.Errorf("failed to push account jwt: %w", err)
+	}
+	return nil
+}
+
+func (c *client) GetAccountJWT(publicKey string) (string, error) {
+	nc, err := c.connectSystem()
+	if err != nil {
+		return "", err
+	}
+	defer nc.Close()
+	msg, err := nc.Request(fmt.Sprintf(claimsLookupSubject, publicKey), nil, apiTimeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to lookup account jwt: %w", resolverError(err))
+	}
+	// The resolver replies with an empty message if it doesn't have the JWT
+	if len(msg.Data) == 0 {
+		return "", ErrNotFound
+	}
+	return string(msg.Data), nil
+}
+
+func (c *client) DeleteAccountJWT(publicKey, operatorSeed string) error {
+	token, err := issueDeleteRequest(publicKey, operatorSeed)
+	if err != nil {
+		return fmt.Errorf("failed to delete account jwt: %w", err)
+	}
+	if err := c.claimsRequest(claimsDeleteSubject, token); err != nil {
+		return fmt.Errorf("failed to delete account jwt: %w", err)
+	}
+	return nil
+}
+
+func (c *client) claimsRequest(subject, token string) error {
+	nc, err := c.connectSystem()
+	if err != nil {
+		return err
+	}
+	defer nc.Close()
+	msg, err := nc.Request(subject, []byte(token), apiTimeout)
+	if err != nil {
+		return resolverError(err)
+	}
+	var resp claimsResponse
+	if err := json.Unmarshal(msg.Data, &resp); err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return nil
+}
+
+// connectSystem connects with the system account credentials instead of
+// the credentials of the client.
+func (c *client) connectSystem() (*nats.Conn, error) {
+	if c.config.SystemCredsFile == "" {
+		return nil, ErrNoSystemCreds
+	}
+	config := c.config
+	config.Token, config.User, config.Password, config.NKeyFile = "", "", "", ""
+	config.CredsFile = c.config.SystemCredsFile
+	system := &client{config: config}
+	return system.dial()
+}
+
+func resolverError(err error) error {
+	if errors.Is(err, nats.ErrNoResponders) {
+		return fmt.Errorf("no account resolver responded, the servers need a full resolver: %w", err)
+	}
+	return err
+}
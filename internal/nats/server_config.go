@@ -0,0 +1,476 @@
+package nats
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// ServerConfig is the content of a nats-server configuration file. Empty
+// fields and nil sections are left out of the file.
+type ServerConfig struct {
+	ServerName    string
+	Listen        string
+	TLS           *ServerTLS
+	JetStream     *ServerJetStream
+	Cluster       *ServerCluster
+	Gateway       *ServerGateway
+	LeafNodes     *ServerLeafNodes
+	Accounts      []ServerAccount
+	SystemAccount string
+	MQTT          *ServerMQTT
+	Websocket     *ServerWebsocket
+}
+
+type ServerTLS struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+	Verify   bool
+}
+
+// ServerJetStream sets the JetStream storage. Zero limits are left to the
+// server, which then uses a share of the memory and disk available.
+type ServerJetStream struct {
+	StoreDir       string
+	MaxMemoryStore int64
+	MaxFileStore   int64
+	Domain         string
+}
+
+type ServerCluster struct {
+	Name   string
+	Listen string
+	Routes []string
+	TLS    *ServerTLS
+}
+
+type ServerGateway struct {
+	Name     string
+	Listen   string
+	Gateways []RemoteGateway
+	TLS      *ServerTLS
+}
+
+type RemoteGateway struct {
+	Name string
+	URLs []string
+}
+
+type ServerLeafNodes struct {
+	Listen  string
+	Remotes []LeafNodeRemote
+	TLS     *ServerTLS
+}
+
+type LeafNodeRemote struct {
+	URLs        []string
+	Account     string
+	Credentials string
+}
+
+type ServerAccount struct {
+	Name      string
+	JetStream bool
+	Users     []ServerUser
+}
+
+// ServerUser authenticates either with User and Password or with NKey.
+type ServerUser struct {
+	User        string
+	Password    string
+	NKey        string
+	Permissions *ServerPermissions
+}
+
+type ServerPermissions struct {
+	PubAllow []string
+	PubDeny  []string
+	SubAllow []string
+	SubDeny  []string
+}
+
+type ServerMQTT struct {
+	Listen string
+	TLS    *ServerTLS
+}
+
+type ServerWebsocket struct {
+	Listen string
+	NoTLS  bool
+	TLS    *ServerTLS
+}
+
+// RenderServerConfig renders the configuration file.
+func RenderServerConfig(config ServerConfig) string {
+	w := &confWriter{}
+	w.str("server_name", config.ServerName)
+	w.str("listen", config.Listen)
+	w.tls(config.TLS)
+	if js := config.JetStream; js != nil {
+		w.open("jetstream")
+		w.str("store_dir", js.StoreDir)
+		w.int("max_memory_store", js.MaxMemoryStore)
+		w.int("max_file_store", js.MaxFileStore)
+		w.str("domain", js.Domain)
+		w.close()
+	}
+	if cluster := config.Cluster; cluster != nil {
+		w.open("cluster")
+		w.str("name", cluster.Name)
+		w.str("listen", cluster.Listen)
+		w.list("routes", cluster.Routes)
+		w.tls(cluster.TLS)
+		w.close()
+	}
+	if gateway := config.Gateway; gateway != nil {
+		w.open("gateway")
+		w.str("name", gateway.Name)
+		w.str("listen", gateway.Listen)
+		if len(gateway.Gateways) > 0 {
+			w.openList("gateways")
+			for _, remote := range gateway.Gateways {
+				w.open("")
+				w.str("name", remote.Name)
+				w.list("urls", remote.URLs)
+				w.close()
+			}
+			w.closeList()
+		}
+		w.tls(gateway.TLS)
+		w.close()
+	}
+	if leafNodes := config.LeafNodes; leafNodes != nil {
+		w.open("leafnodes")
+		w.str("listen", leafNodes.Listen)
+		if len(leafNodes.Remotes) > 0 {
+			w.openList("remotes")
+			for _, remote := range leafNodes.Remotes {
+				w.open("")
+				w.list("urls", remote.URLs)
+				w.str("account", remote.Account)
+				w.str("credentials", remote.Credentials)
+				w.close()
+			}
+			w.closeList()
+		}
+		w.tls(leafNodes.TLS)
+		w.close()
+	}
+	if len(config.Accounts) > 0 {
+		w.open("accounts")
+		for _, account := range config.Accounts {
+			w.open(quoteConf(account.Name))
+			if account.JetStream {
+				w.line("jetstream: enabled")
+			}
+			if len(account.Users) > 0 {
+				w.openList("users")
+				for _, user := range account.Users {
+					w.user(user)
+				}
+				w.closeList()
+			}
+			w.close()
+		}
+		w.close()
+	}
+	w.str("system_account", config.SystemAccount)
+	if mqtt := config.MQTT; mqtt != nil {
+		w.open("mqtt")
+		w.str("listen", mqtt.Listen)
+		w.tls(mqtt.TLS)
+		w.close()
+	}
+	if websocket := config.Websocket; websocket != nil {
+		w.open("websocket")
+		w.str("listen", websocket.Listen)
+		if websocket.NoTLS {
+			w.line("no_tls: true")
+		}
+		w.tls(websocket.TLS)
+		w.close()
+	}
+	return w.String()
+}
+
+// ValidateServerConfig parses the rendered configuration file with the
+// parser of the server, returning the warnings of the parser. The TLS files
+// live on the servers, so they are replaced with a generated certificate.
+func ValidateServerConfig(config ServerConfig) ([]string, error) {
+	dir, err := os.MkdirTemp("", "nats-server-config")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile, err := writeTestCertificate(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate test certificate: %w", err)
+	}
+	replace := func(tls *ServerTLS) *ServerTLS {
+		if tls == nil {
+			return nil
+		}
+		replaced := *tls
+		for _, file := range []*string{&replaced.CertFile, &replaced.CAFile} {
+			if *file != "" {
+				*file = certFile
+			}
+		}
+		if replaced.KeyFile != "" {
+			replaced.KeyFile = keyFile
+		}
+		return &replaced
+	}
+	config.TLS = replace(config.TLS)
+	if config.Cluster != nil {
+		cluster := *config.Cluster
+		cluster.TLS = replace(cluster.TLS)
+		config.Cluster = &cluster
+	}
+	if config.Gateway != nil {
+		gateway := *config.Gateway
+		gateway.TLS = replace(gateway.TLS)
+		config.Gateway = &gateway
+	}
+	if config.LeafNodes != nil {
+		leafNodes := *config.LeafNodes
+		leafNodes.TLS = replace(leafNodes.TLS)
+		config.LeafNodes = &leafNodes
+	}
+	if config.MQTT != nil {
+		mqtt := *config.MQTT
+		mqtt.TLS = replace(mqtt.TLS)
+		config.MQTT = &mqtt
+	}
+	if config.Websocket != nil {
+		websocket := *config.Websocket
+		websocket.TLS = replace(websocket.TLS)
+		config.Websocket = &websocket
+	}
+
+	file := filepath.Join(dir, "nats-server.conf")
+	if err := os.WriteFile(file, []byte(RenderServerConfig(config)), 0o600); err != nil {
+		return nil, err
+	}
+	err = (&server.Options{}).ProcessConfigFile(file)
+	if err == nil {
+		return nil, nil
+	}
+	// Report the positions relative to the file instead of the temporary path
+	format := func(err error) string {
+		return strings.ReplaceAll(err.Error(), file+":", "line ")
+	}
+	var processErr interface {
+		Errors() []error
+		Warnings() []error
+	}
+	if !errors.As(err, &processErr) {
+		return nil, errors.New(format(err))
+	}
+	var warnings, errs []string
+	for _, warning := range processErr.Warnings() {
+		warnings = append(warnings, format(warning))
+	}
+	for _, err := range processErr.Errors() {
+		errs = append(errs, format(err))
+	}
+	// The server reports in map order
+	sortByLine(warnings)
+	sortByLine(errs)
+	if len(errs) > 0 {
+		return warnings, errors.New(strings.Join(errs, "\n"))
+	}
+	return warnings, nil
+}
+
+// sortByLine sorts the messages by their position in the file, messages
+// without a position first.
+func sortByLine(messages []string) {
+	position := func(message string) (line, column int) {
+		fmt.Sscanf(message, "line %d:%d:", &line, &column)
+		return line, column
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		li, ci := position(messages[i])
+		lj, cj := position(messages[j])
+		return li < lj || li == lj && ci < cj
+	})
+}
+
+// writeTestCertificate writes a self-signed certificate and its key to dir.
+func writeTestCertificate(dir string) (string, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return "", "", err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0o600); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return "", "", err
+	}
+	return certFile, keyFile, nil
+}
+
+// confWriter writes the nats-server configuration format, indenting the
+// nested maps and lists.
+type confWriter struct {
+	strings.Builder
+	depth int
+}
+
+func (w *confWriter) line(format string, args ...any) {
+	w.WriteString(strings.Repeat("  ", w.depth))
+	fmt.Fprintf(w, format, args...)
+	w.WriteString("\n")
+}
+
+// open opens a map, an anonymous one for an empty key.
+func (w *confWriter) open(key string) {
+	if key == "" {
+		w.line("{")
+	} else {
+		w.line("%s {", key)
+	}
+	w.depth++
+}
+
+func (w *confWriter) close() {
+	w.depth--
+	w.line("}")
+}
+
+func (w *confWriter) openList(key string) {
+	w.line("%s: [", key)
+	w.depth++
+}
+
+func (w *confWriter) closeList() {
+	w.depth--
+	w.line("]")
+}
+
+func (w *confWriter) str(key, value string) {
+	if value != "" {
+		w.line("%s: %s", key, quoteConf(value))
+	}
+}
+
+func (w *confWriter) int(key string, value int64) {
+	if value != 0 {
+		w.line("%s: %d", key, value)
+	}
+}
+
+func (w *confWriter) list(key string, values []string) {
+	if len(values) > 0 {
+		quoted := make([]string, len(values))
+		for i, value := range values {
+			quoted[i] = quoteConf(value)
+		}
+		w.line("%s: [%s]", key, strings.Join(quoted, ", "))
+	}
+}
+
+func (w *confWriter) tls(tls *ServerTLS) {
+	if tls == nil {
+		return
+	}
+	w.open("tls")
+	w.str("cert_file", tls.CertFile)
+	w.str("key_file", tls.KeyFile)
+	w.str("ca_file", tls.CAFile)
+	if tls.Verify {
+		w.line("verify: true")
+	}
+	w.close()
+}
+
+func (w *confWriter) user(user ServerUser) {
+	w.open("")
+	w.str("user", user.User)
+	w.str("password", user.Password)
+	w.str("nkey", user.NKey)
+	if p := user.Permissions; p != nil {
+		w.open("permissions")
+		for _, direction := range []struct {
+			key         string
+			allow, deny []string
+		}{
+			{"publish", p.PubAllow, p.PubDeny},
+			{"subscribe", p.SubAllow, p.SubDeny},
+		} {
+			if len(direction.allow) == 0 && len(direction.deny) == 0 {
+				continue
+			}
+			w.open(direction.key)
+			w.list("allow", direction.allow)
+			w.list("deny", direction.deny)
+			w.close()
+		}
+		w.close()
+	}
+	w.close()
+}
+
+// quoteConf quotes a string with the escapes of the configuration format.
+// Quoted strings aren't expanded as $variables.
+func quoteConf(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\x%02x`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,255 @@
+package nats
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	snapshotMetadataFile = "backup.json"
+	snapshotDataFile     = "stream.tar.s2"
+	snapshotChunkSize    = 128 * 1024
+	// snapshotTimeout bounds the wait for the next chunk, and for the server
+	// to rebuild the stream once a restore is uploaded.
+	snapshotTimeout = time.Minute
+)
+
+// SnapshotOptions configure a stream snapshot.
+type SnapshotOptions struct {
+	NoConsumers bool
+	CheckMsgs   bool
+}
+
+// Snapshot is the metadata of a stream snapshot written to a directory.
+type Snapshot struct {
+	Config   nats.StreamConfig `json:"config"`
+	State    nats.StreamState  `json:"state"`
+	Checksum string            `json:"checksum"`
+	Size     int64             `json:"size"`
+}
+
+type streamSnapshotRequest struct {
+	DeliverSubject string `json:"deliver_subject"`
+	NoConsumers    bool   `json:"no_consumers,omitempty"`
+	ChunkSize      int    `json:"chunk_size,omitempty"`
+	CheckMsgs      bool   `json:"jsck,omitempty"`
+}
+
+type streamSnapshotResponse struct {
+	apiResponse
+	Config *nats.StreamConfig `json:"config"`
+	State  *nats.StreamState  `json:"state"`
+}
+
+type streamRestoreRequest struct {
+	Config nats.StreamConfig `json:"config"`
+	State  nats.StreamState  `json:"state"`
+}
+
+type streamRestoreResponse struct {
+	apiResponse
+	DeliverSubject string `json:"deliver_subject"`
+}
+
+type streamInfoResponse struct {
+	apiResponse
+	*nats.StreamInfo
+}
+
+// SnapshotStream writes a snapshot of the stream to dir, as the stream data
+// and a metadata file holding the stream config, state and data checksum.
+func (c *client) SnapshotStream(streamName, dir string, opts SnapshotOptions) (Snapshot, error) {
+	nc, _, err := c.connect()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer nc.Close()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	file, err := os.Create(filepath.Join(dir, snapshotDataFile))
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer file.Close()
+
+	inbox := nc.NewInbox()
+	sub, err := nc.SubscribeSync(inbox)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to subscribe to snapshot chunks: %w", err)
+	}
+	defer sub.Unsubscribe() //nolint:errcheck
+	var resp streamSnapshotResponse
+	req := streamSnapshotRequest{
+		DeliverSubject: inbox,
+		NoConsumers:    opts.NoConsumers,
+		ChunkSize:      snapshotChunkSize,
+		CheckMsgs:      opts.CheckMsgs,
+	}
+	if err := c.apiRequest(nc, "STREAM.SNAPSHOT."+streamName, req, &resp); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to snapshot stream: %w", err)
+	}
+	if resp.Error != nil {
+		if resp.Error.ErrorCode == int(nats.JSErrCodeStreamNotFound) {
+			return Snapshot{}, ErrNotFound
+		}
+		return Snapshot{}, fmt.Errorf("failed to snapshot stream: %w", resp.Error)
+	}
+
+	hash := sha256.New()
+	out := io.MultiWriter(file, hash)
+	var size int64
+	for {
+		msg, err := sub.NextMsg(snapshotTimeout)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("failed to receive snapshot chunk: %w", err)
+		}
+		// An empty message ends the snapshot, with a status header on failure.
+		// Newer servers end successful snapshots with a 204 status.
+		if len(msg.Data) == 0 {
+			if status := msg.Header.Get("Status"); status != "" && status != "204" {
+				return Snapshot{}, fmt.Errorf("snapshot failed: %s %s", status, msg.Header.Get("Description"))
+			}
+			break
+		}
+		if _, err := out.Write(msg.Data); err != nil {
+			return Snapshot{}, fmt.Errorf("failed to write snapshot chunk: %w", err)
+		}
+		size += int64(len(msg.Data))
+		// Acknowledging chunks lets the server send more, it only keeps a
+		// window of unacknowledged chunks in flight.
+		if msg.Reply != "" {
+			if err := msg.Respond(nil); err != nil {
+				return Snapshot{}, fmt.Errorf("failed to acknowledge snapshot chunk: %w", err)
+			}
+		}
+	}
+	if err := file.Close(); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	snapshot := Snapshot{
+		Config:   *resp.Config,
+		State:    *resp.State,
+		Checksum: hex.EncodeToString(hash.Sum(nil)),
+		Size:     size,
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotMetadataFile), data, 0o600); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	return snapshot, nil
+}
+
+// ReadSnapshot reads the snapshot metadata from dir and verifies the checksum
+// of the snapshot data. It returns ErrNotFound if there is no snapshot in dir.
+func ReadSnapshot(dir string) (Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, snapshotMetadataFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Snapshot{}, ErrNotFound
+		}
+		return Snapshot{}, fmt.Errorf("failed to read snapshot metadata: %w", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to parse snapshot metadata: %w", err)
+	}
+	file, err := os.Open(filepath.Join(dir, snapshotDataFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Snapshot{}, ErrNotFound
+		}
+		return Snapshot{}, fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+	if checksum := hex.EncodeToString(hash.Sum(nil)); checksum != snapshot.Checksum {
+		return Snapshot{}, fmt.Errorf("snapshot checksum mismatch: expected %s, got %s", snapshot.Checksum, checksum)
+	}
+	return snapshot, nil
+}
+
+// RestoreStream creates the stream from the snapshot in dir, after verifying
+// its checksum. The stream is restored under streamName.
+func (c *client) RestoreStream(streamName, dir string) (StreamInfo, error) {
+	snapshot, err := ReadSnapshot(dir)
+	if err != nil {
+		return StreamInfo{}, err
+	}
+	file, err := os.Open(filepath.Join(dir, snapshotDataFile))
+	if err != nil {
+		return StreamInfo{}, fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer file.Close()
+
+	nc, _, err := c.connect()
+	if err != nil {
+		return StreamInfo{}, err
+	}
+	defer nc.Close()
+	var resp streamRestoreResponse
+	req := streamRestoreRequest{Config: snapshot.Config, State: snapshot.State}
+	req.Config.Name = streamName
+	if err := c.apiRequest(nc, "STREAM.RESTORE."+streamName, req, &resp); err != nil {
+		return StreamInfo{}, fmt.Errorf("failed to restore stream: %w", err)
+	}
+	if resp.Error != nil {
+		return StreamInfo{}, fmt.Errorf("failed to restore stream: %w", resp.Error)
+	}
+
+	// Every chunk is acknowledged by the server before the next one is sent
+	chunk := make([]byte, snapshotChunkSize)
+	for {
+		n, err := file.Read(chunk)
+		if n > 0 {
+			msg, err := nc.Request(resp.DeliverSubject, chunk[:n], apiTimeout)
+			if err != nil {
+				return StreamInfo{}, fmt.Errorf("failed to upload snapshot chunk: %w", err)
+			}
+			if len(msg.Data) > 0 {
+				var chunkResp apiResponse
+				if err := json.Unmarshal(msg.Data, &chunkResp); err == nil && chunkResp.Error != nil {
+					return StreamInfo{}, fmt.Errorf("failed to upload snapshot chunk: %w", chunkResp.Error)
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return StreamInfo{}, fmt.Errorf("failed to read snapshot file: %w", err)
+		}
+	}
+	// An empty message ends the upload, the reply is the restored stream
+	msg, err := nc.Request(resp.DeliverSubject, nil, snapshotTimeout)
+	if err != nil {
+		return StreamInfo{}, fmt.Errorf("failed to complete restore: %w", err)
+	}
+	var infoResp streamInfoResponse
+	if err := json.Unmarshal(msg.Data, &infoResp); err != nil {
+		return StreamInfo{}, fmt.Errorf("failed to complete restore: %w", err)
+	}
+	if infoResp.Error != nil {
+		return StreamInfo{}, fmt.Errorf("failed to complete restore: %w", infoResp.Error)
+	}
+	if infoResp.StreamInfo == nil {
+		return StreamInfo{}, errors.New("failed to complete restore: empty response")
+	}
+	return StreamInfo(*infoResp.StreamInfo), nil
+}
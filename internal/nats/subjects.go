@@ -0,0 +1,66 @@
+package nats
+
+import (
+	"strings"
+)
+
+// ValidSubject reports whether the subject is a valid nats subject, wildcards
+// allowed: non empty tokens without whitespace, * standing alone in a token
+// and > only as the last token.
+func ValidSubject(subject string) bool {
+	if subject == "" {
+		return false
+	}
+	tokens := strings.Split(subject, ".")
+	for i, token := range tokens {
+		switch {
+		case token == "":
+			return false
+		case strings.ContainsAny(token, " \t\r\n"):
+			return false
+		case token == ">" && i != len(tokens)-1:
+			return false
+		case len(token) > 1 && strings.ContainsAny(token, "*>"):
+			return false
+		}
+	}
+	return true
+}
+
+// TokenizeSubject splits the subject into its tokens.
+func TokenizeSubject(subject string) []string {
+	return strings.Split(subject, ".")
+}
+
+// SubjectMatches reports whether every subject matched by subject is also
+// matched by pattern. For a subject without wildcards, it is whether the
+// pattern matches it.
+func SubjectMatches(subject, pattern string) bool {
+	s, p := TokenizeSubject(subject), TokenizeSubject(pattern)
+	for i, token := range p {
+		if token == ">" {
+			return i < len(s)
+		}
+		if i >= len(s) || s[i] == ">" {
+			return false
+		}
+		if token != "*" && token != s[i] {
+			return false
+		}
+	}
+	return len(s) == len(p)
+}
+
+// SubjectsOverlap reports whether a subject is matched by both a and b.
+func SubjectsOverlap(a, b string) bool {
+	ta, tb := TokenizeSubject(a), TokenizeSubject(b)
+	for i := 0; i < len(ta) && i < len(tb); i++ {
+		if ta[i] == ">" || tb[i] == ">" {
+			return true
+		}
+		if ta[i] != "*" && tb[i] != "*" && ta[i] != tb[i] {
+			return false
+		}
+	}
+	return len(ta) == len(tb)
+}
@@ -1,21 +1,75 @@
 package nats
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/nats-io/nats.go"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrUnauthorized        = errors.New("authorization failed")
+	ErrJetStreamNotEnabled = errors.New("jetstream not enabled")
+	ErrNoSystemCreds       = errors.New("no system account credentials")
+)
+
+// PublishOptions are the JetStream publish guards.
+type PublishOptions struct {
+	MsgID                string
+	ExpectedStream       string
+	ExpectedLastSequence *uint64
+}
+
+// PurgeOptions narrow down a stream purge. Sequence and Keep are exclusive.
+type PurgeOptions struct {
+	Subject  string
+	Sequence uint64
+	Keep     uint64
+}
 
 type (
 	StreamConfig nats.StreamConfig
 	StreamInfo   nats.StreamInfo
 
-	ConsumerConfig nats.ConsumerConfig
-	ConsumerInfo   nats.ConsumerInfo
+	StreamMessage nats.RawStreamMsg
+	PubAck        nats.PubAck
+
+	AccountInfo nats.AccountInfo
+	AccountTier nats.Tier
 )
 
+// ConsumerConfig extends the nats.go consumer config with the settings it
+// doesn't know about yet.
+type ConsumerConfig struct {
+	nats.ConsumerConfig
+	PauseUntil *time.Time `json:"pause_until,omitempty"`
+
+	PriorityGroups  []string       `json:"priority_groups,omitempty"`
+	PriorityPolicy  PriorityPolicy `json:"priority_policy,omitempty"`
+	PriorityTimeout time.Duration  `json:"priority_timeout,omitempty"`
+}
+
+// Filters returns the filter subjects of the consumer, including the single
+// filter subject set by older tooling.
+func (c ConsumerConfig) Filters() []string {
+	if len(c.FilterSubjects) == 0 && c.FilterSubject != "" {
+		return []string{c.FilterSubject}
+	}
+	return c.FilterSubjects
+}
+
+// ConsumerInfo extends the nats.go consumer info with the state it doesn't
+// know about yet. Config shadows the embedded one.
+type ConsumerInfo struct {
+	nats.ConsumerInfo
+	Config         ConsumerConfig `json:"config"`
+	Paused         bool           `json:"paused,omitempty"`
+	PauseRemaining time.Duration  `json:"pause_remaining,omitempty"`
+}
+
 var (
 	storageType = map[string]nats.StorageType{
 		"file":   nats.FileStorage,
@@ -68,3 +122,55 @@ var (
 	ToDeliverPolicy       = mapFn(deliverPolicy)
 	FromDeliverPolicy     = mapFn(invertedDeliverPolicy)
 )
+
+// PriorityPolicy is how a pull consumer serves the clients of its priority
+// groups.
+type PriorityPolicy int
+
+const (
+	PriorityNone PriorityPolicy = iota
+	PriorityOverflow
+	PriorityPinnedClient
+)
+
+// PriorityUnknown is a policy of a newer server that this provider doesn't
+// know. It is read as "unknown" and can't be sent back to the server.
+const PriorityUnknown PriorityPolicy = -1
+
+func (p PriorityPolicy) MarshalJSON() ([]byte, error) {
+	name, ok := invertedPriorityPolicy[p]
+	if !ok {
+		return nil, fmt.Errorf("unknown priority policy: %d", p)
+	}
+	return json.Marshal(name)
+}
+
+func (p *PriorityPolicy) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	policy, ok := priorityPolicy[name]
+	if !ok {
+		policy = PriorityUnknown
+	}
+	*p = policy
+	return nil
+}
+
+var (
+	priorityPolicy = map[string]PriorityPolicy{
+		"none":          PriorityNone,
+		"overflow":      PriorityOverflow,
+		"pinned_client": PriorityPinnedClient,
+	}
+	invertedPriorityPolicy = invertMap(priorityPolicy)
+	ToPriorityPolicy       = mapFn(priorityPolicy)
+)
+
+func FromPriorityPolicy(p PriorityPolicy) string {
+	if name, ok := invertedPriorityPolicy[p]; ok {
+		return name
+	}
+	return "unknown"
+}
@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &accountInfoDataSource{}
+
+// NewAccountInfoDataSource creates a new account info datasource.
+func NewAccountInfoDataSource() datasource.DataSource {
+	return &accountInfoDataSource{}
+}
+
+type accountInfoDataSource struct {
+	client nats.Client
+}
+
+type accountInfoDataSourceModel struct {
+	Domain types.String `tfsdk:"domain"`
+
+	ServerDomain types.String                `tfsdk:"server_domain"`
+	Memory       types.Int64                 `tfsdk:"memory"`
+	Storage      types.Int64                 `tfsdk:"storage"`
+	Streams      types.Int64                 `tfsdk:"streams"`
+	Consumers    types.Int64                 `tfsdk:"consumers"`
+	Limits       accountLimitsModel          `tfsdk:"limits"`
+	API          accountAPIStatsModel        `tfsdk:"api"`
+	Tiers        map[string]accountTierModel `tfsdk:"tiers"`
+}
+
+type accountTierModel struct {
+	Memory    types.Int64        `tfsdk:"memory"`
+	Storage   types.Int64        `tfsdk:"storage"`
+	Streams   types.Int64        `tfsdk:"streams"`
+	Consumers types.Int64        `tfsdk:"consumers"`
+	Limits    accountLimitsModel `tfsdk:"limits"`
+}
+
+type accountLimitsModel struct {
+	MaxMemory             types.Int64 `tfsdk:"max_memory"`
+	MaxStorage            types.Int64 `tfsdk:"max_storage"`
+	MaxStreams            types.Int64 `tfsdk:"max_streams"`
+	MaxConsumers          types.Int64 `tfsdk:"max_consumers"`
+	MaxAckPending         types.Int64 `tfsdk:"max_ack_pending"`
+	MemoryMaxStreamBytes  types.Int64 `tfsdk:"memory_max_stream_bytes"`
+	StorageMaxStreamBytes types.Int64 `tfsdk:"storage_max_stream_bytes"`
+	MaxBytesRequired      types.Bool  `tfsdk:"max_bytes_required"`
+}
+
+type accountAPIStatsModel struct {
+	Total  types.Int64 `tfsdk:"total"`
+	Errors types.Int64 `tfsdk:"errors"`
+}
+
+func (d *accountInfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account_info"
+}
+
+func (d *accountInfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := accountTierAttributes()
+	attributes["domain"] = schema.StringAttribute{
+		Description: "The JetStream domain to query. Defaults to the provider's jetstream_domain",
+		Optional:    true,
+	}
+	attributes["server_domain"] = schema.StringAttribute{
+		Description: "The JetStream domain reported by the server, empty if JetStream has no domain",
+		Computed:    true,
+	}
+	attributes["api"] = schema.SingleNestedAttribute{
+		Description: "Statistics of the JetStream API calls made by the account",
+		Computed:    true,
+		Attributes: map[string]schema.Attribute{
+			"total": schema.Int64Attribute{
+				Description: "Total number of API calls",
+				Computed:    true,
+			},
+			"errors": schema.Int64Attribute{
+				Description: "Number of API calls that resulted in an error",
+				Computed:    true,
+			},
+		},
+	}
+	attributes["tiers"] = schema.MapNestedAttribute{
+		Description: "Usage and limits per tier, keyed by tier name (e.g. R1, R3), when the account uses tiered limits",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: accountTierAttributes(),
+		},
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Account info data source, exposes the JetStream usage and limits of the account",
+		Attributes:          attributes,
+	}
+}
+
+func accountTierAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"memory": schema.Int64Attribute{
+			Description: "Memory used by the streams, in bytes",
+			Computed:    true,
+		},
+		"storage": schema.Int64Attribute{
+			Description: "File storage used by the streams, in bytes",
+			Computed:    true,
+		},
+		"streams": schema.Int64Attribute{
+			Description: "Number of streams",
+			Computed:    true,
+		},
+		"consumers": schema.Int64Attribute{
+			Description: "Number of consumers",
+			Computed:    true,
+		},
+		"limits": schema.SingleNestedAttribute{
+			Description: "The JetStream limits, -1 for unlimited",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"max_memory": schema.Int64Attribute{
+					Description: "Maximum memory storage, in bytes",
+					Computed:    true,
+				},
+				"max_storage": schema.Int64Attribute{
+					Description: "Maximum file storage, in bytes",
+					Computed:    true,
+				},
+				"max_streams": schema.Int64Attribute{
+					Description: "Maximum number of streams",
+					Computed:    true,
+				},
+				"max_consumers": schema.Int64Attribute{
+					Description: "Maximum number of consumers",
+					Computed:    true,
+				},
+				"max_ack_pending": schema.Int64Attribute{
+					Description: "Maximum ack pending allowed per consumer",
+					Computed:    true,
+				},
+				"memory_max_stream_bytes": schema.Int64Attribute{
+					Description: "Maximum max_bytes of a memory stream",
+					Computed:    true,
+				},
+				"storage_max_stream_bytes": schema.Int64Attribute{
+					Description: "Maximum max_bytes of a file stream",
+					Computed:    true,
+				},
+				"max_bytes_required": schema.BoolAttribute{
+					Description: "Whether streams must set max_bytes",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func (d *accountInfoDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data.Client
+	resp.Diagnostics.Append(data.checkServer()...)
+}
+
+func (d *accountInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// 1. Read config
+	var domain types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("domain"), &domain)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Read the account info
+	accountInfo, err := d.client.WithDomain(domain.ValueString()).GetAccountInfo()
+	if err != nil {
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to get account info: %s", err))
+		return
+	}
+	// 3. Write state
+	state := fromAccountInfo(accountInfo)
+	state.Domain = domain
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func fromAccountInfo(accountInfo nats.AccountInfo) accountInfoDataSourceModel {
+	tier := fromAccountTier(nats.AccountTier(accountInfo.Tier))
+	var tiers map[string]accountTierModel
+	if len(accountInfo.Tiers) > 0 {
+		tiers = make(map[string]accountTierModel, len(accountInfo.Tiers))
+		for name, tier := range accountInfo.Tiers {
+			tiers[name] = fromAccountTier(nats.AccountTier(tier))
+		}
+	}
+	return accountInfoDataSourceModel{
+		ServerDomain: types.StringValue(accountInfo.Domain),
+		Memory:       tier.Memory,
+		Storage:      tier.Storage,
+		Streams:      tier.Streams,
+		Consumers:    tier.Consumers,
+		Limits:       tier.Limits,
+		API: accountAPIStatsModel{
+			Total:  types.Int64Value(int64(accountInfo.API.Total)),
+			Errors: types.Int64Value(int64(accountInfo.API.Errors)),
+		},
+		Tiers: tiers,
+	}
+}
+
+func fromAccountTier(tier nats.AccountTier) accountTierModel {
+	return accountTierModel{
+		Memory:    types.Int64Value(int64(tier.Memory)),
+		Storage:   types.Int64Value(int64(tier.Store)),
+		Streams:   types.Int64Value(int64(tier.Streams)),
+		Consumers: types.Int64Value(int64(tier.Consumers)),
+		Limits: accountLimitsModel{
+			MaxMemory:             types.Int64Value(tier.Limits.MaxMemory),
+			MaxStorage:            types.Int64Value(tier.Limits.MaxStore),
+			MaxStreams:            types.Int64Value(int64(tier.Limits.MaxStreams)),
+			MaxConsumers:          types.Int64Value(int64(tier.Limits.MaxConsumers)),
+			MaxAckPending:         types.Int64Value(int64(tier.Limits.MaxAckPending)),
+			MemoryMaxStreamBytes:  types.Int64Value(tier.Limits.MemoryMaxStreamBytes),
+			StorageMaxStreamBytes: types.Int64Value(tier.Limits.StoreMaxStreamBytes),
+			MaxBytesRequired:      types.BoolValue(tier.Limits.MaxBytesRequired),
+		},
+	}
+}
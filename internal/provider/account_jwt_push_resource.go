@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &accountJWTPushResource{}
+
+func NewAccountJWTPushResource() resource.Resource {
+	return &accountJWTPushResource{}
+}
+
+type accountJWTPushResource struct {
+	client nats.Client
+}
+
+type accountJWTPushResourceModel struct {
+	JWT          types.String `tfsdk:"jwt"`
+	OperatorSeed types.String `tfsdk:"operator_seed"`
+
+	PublicKey types.String `tfsdk:"public_key"`
+}
+
+func (r *accountJWTPushResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account_jwt_push"
+}
+
+func (r *accountJWTPushResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Account JWT push resource, uploads an account JWT to the full resolver of the servers through the system account, set with the provider's system_account_creds. A JWT changed on the resolver outside terraform is pushed again",
+		Attributes: map[string]schema.Attribute{
+			"jwt": schema.StringAttribute{
+				Description: "The account JWT to push, e.g. the jwt of a nats_account",
+				Required:    true,
+			},
+			"operator_seed": schema.StringAttribute{
+				Description: "The seed of the operator nkey, or of one of the operator signing keys, to sign the request deleting the JWT from the resolver on destroy. If not set, destroying the resource keeps the JWT on the resolver",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"public_key": schema.StringAttribute{
+				Description: "The public key of the account",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *accountJWTPushResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(nats.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected nats.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *accountJWTPushResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// 1. Read plan
+	var data accountJWTPushResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	publicKey, err := nats.AccountPublicKey(data.JWT.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid account JWT", err.Error())
+		return
+	}
+	// 2. Push the JWT
+	if err := r.client.PushAccountJWT(data.JWT.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to push account JWT: %s", err))
+		return
+	}
+	// 3. Write state
+	data.PublicKey = types.StringValue(publicKey)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *accountJWTPushResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// 1. Read current state
+	var data accountJWTPushResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Fetch the JWT stored in the resolver
+	token, err := r.client.GetAccountJWT(data.PublicKey.ValueString())
+	if err != nil {
+		if errors.Is(err, nats.ErrNotFound) {
+			resp.Diagnostics.AddWarning("Resource not found", "couldn't find the account JWT, possibly deleted outside terraform")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to read account JWT: %s", err))
+		return
+	}
+	// 3. Write new state, a different JWT shows as drift
+	data.JWT = types.StringValue(token)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *accountJWTPushResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// 1. Read plan and current state
+	var data, state accountJWTPushResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	publicKey, err := nats.AccountPublicKey(data.JWT.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid account JWT", err.Error())
+		return
+	}
+	if publicKey != state.PublicKey.ValueString() {
+		resp.Diagnostics.AddError("Invalid account JWT", fmt.Sprintf("The JWT is for account %s instead of %s, the account of a push can't be changed", publicKey, state.PublicKey.ValueString()))
+		return
+	}
+	// 2. Push the new JWT
+	if err := r.client.PushAccountJWT(data.JWT.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to push account JWT: %s", err))
+		return
+	}
+	// 3. Write new state
+	data.PublicKey = types.StringValue(publicKey)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *accountJWTPushResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// 1. Read current state
+	var data accountJWTPushResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.OperatorSeed.IsNull() {
+		resp.Diagnostics.AddWarning("Account JWT kept", "operator_seed is not set, the account JWT stays on the resolver")
+		return
+	}
+	// 2. Delete the JWT
+	if err := r.client.DeleteAccountJWT(data.PublicKey.ValueString(), data.OperatorSeed.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to delete account JWT: %s", err))
+	}
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// storageReservation is the storage a stream-backed resource reserves in its
+// account, i.e. max_bytes on every replica.
+type storageReservation struct {
+	Storage  string
+	Replicas int64
+	MaxBytes int64
+}
+
+func (s storageReservation) bytes() int64 {
+	if s.MaxBytes <= 0 {
+		return 0
+	}
+	return s.MaxBytes * s.Replicas
+}
+
+// additionalBytes returns the bytes the planned reservation adds to the
+// current one. A reservation moved to another storage or tier adds all its
+// bytes.
+func (s storageReservation) additionalBytes(current *storageReservation) int64 {
+	additional := s.bytes()
+	if current != nil && current.Storage == s.Storage && current.Replicas == s.Replicas {
+		additional -= current.bytes()
+	}
+	return additional
+}
+
+// attributeGetter is implemented by tfsdk.Plan, tfsdk.State and tfsdk.Config.
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+// getStorageReservation reads the reservation from the storage, num_replicas
+// and max_bytes attributes. It returns false if any of them is not known yet.
+func getStorageReservation(ctx context.Context, data attributeGetter) (storageReservation, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var storage types.String
+	var replicas, maxBytes types.Int64
+	diags.Append(data.GetAttribute(ctx, path.Root("storage"), &storage)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("num_replicas"), &replicas)...)
+	diags.Append(data.GetAttribute(ctx, path.Root("max_bytes"), &maxBytes)...)
+	if diags.HasError() || storage.IsUnknown() || replicas.IsUnknown() || maxBytes.IsUnknown() {
+		return storageReservation{}, false, diags
+	}
+	return storageReservation{
+		Storage:  storage.ValueString(),
+		Replicas: replicas.ValueInt64(),
+		MaxBytes: maxBytes.ValueInt64(),
+	}, true, diags
+}
+
+// checkStorageReservation validates the planned reservation against the
+// JetStream limits of the account tier it falls in. Reservations that can
+// never fit are errors, ones that don't fit the current usage together with
+// the other planned reservations are warnings.
+func checkStorageReservation(accountInfo nats.AccountInfo, planned storageReservation, current *storageReservation, others []storageReservation) diag.Diagnostics {
+	var diags diag.Diagnostics
+	tierName := fmt.Sprintf("R%d", planned.Replicas)
+	tier := nats.AccountTier(accountInfo.Tier)
+	if accountTier, ok := accountInfo.Tiers[tierName]; ok {
+		tier = nats.AccountTier(accountTier)
+	} else {
+		tierName = "account"
+	}
+
+	limit, used, maxStreamBytes := tier.Limits.MaxStore, int64(tier.Store), tier.Limits.StoreMaxStreamBytes
+	if planned.Storage == "memory" {
+		limit, used, maxStreamBytes = tier.Limits.MaxMemory, int64(tier.Memory), tier.Limits.MemoryMaxStreamBytes
+	}
+
+	if tier.Limits.MaxBytesRequired && planned.MaxBytes <= 0 {
+		diags.AddAttributeError(
+			path.Root("max_bytes"),
+			"max_bytes required",
+			fmt.Sprintf("The %s JetStream limits require streams to set max_bytes.", tierName),
+		)
+		return diags
+	}
+	if maxStreamBytes > 0 && planned.MaxBytes > maxStreamBytes {
+		diags.AddAttributeError(
+			path.Root("max_bytes"),
+			"max_bytes exceeds account limits",
+			fmt.Sprintf("max_bytes is %d but the %s JetStream limits allow at most %d bytes per %s stream.", planned.MaxBytes, tierName, maxStreamBytes, planned.Storage),
+		)
+		return diags
+	}
+	if limit < 0 || planned.bytes() == 0 {
+		return diags
+	}
+	if planned.bytes() > limit {
+		diags.AddAttributeError(
+			path.Root("max_bytes"),
+			"Stream exceeds account limits",
+			fmt.Sprintf("The stream reserves %d bytes of %s storage (max_bytes × num_replicas) but the %s JetStream limit is %d bytes.", planned.bytes(), planned.Storage, tierName, limit),
+		)
+		return diags
+	}
+	additional := planned.additionalBytes(current)
+	if additional <= 0 {
+		return diags
+	}
+	// The other planned streams of the same storage and tier share the limit
+	var othersAdditional int64
+	for _, other := range others {
+		if other.Storage == planned.Storage && (tierName == "account" || other.Replicas == planned.Replicas) {
+			othersAdditional += other.bytes()
+		}
+	}
+	switch {
+	case used+additional > limit:
+		diags.AddAttributeWarning(
+			path.Root("max_bytes"),
+			"Stream may exceed account limits",
+			fmt.Sprintf("The stream reserves %d more bytes of %s storage but only %d of the %d bytes allowed by the %s JetStream limits are unused. The apply may fail with insufficient resources.", additional, planned.Storage, limit-used, limit, tierName),
+		)
+	case used+othersAdditional+additional > limit:
+		diags.AddAttributeWarning(
+			path.Root("max_bytes"),
+			"Planned streams may exceed account limits",
+			fmt.Sprintf("The stream reserves %d more bytes of %s storage and the other planned streams %d more, but only %d of the %d bytes allowed by the %s JetStream limits are unused. The apply may fail with insufficient resources.", additional, planned.Storage, othersAdditional, limit-used, limit, tierName),
+		)
+	}
+	return diags
+}
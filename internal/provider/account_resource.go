@@ -0,0 +1,554 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.ResourceWithConfigure  = &accountResource{}
+	_ resource.ResourceWithModifyPlan = &accountResource{}
+)
+
+func NewAccountResource() resource.Resource {
+	return &accountResource{}
+}
+
+// accountResource needs no server connection, the JWT is issued locally.
+type accountResource struct {
+	planned *plannedResources
+}
+
+type accountResourceModel struct {
+	Name        types.String   `tfsdk:"name"`
+	PublicKey   types.String   `tfsdk:"public_key"`
+	SigningSeed types.String   `tfsdk:"signing_seed"`
+	SigningKeys []types.String `tfsdk:"signing_keys"`
+
+	MaxConnections   types.Int64 `tfsdk:"max_connections"`
+	MaxLeafNodes     types.Int64 `tfsdk:"max_leaf_nodes"`
+	MaxSubscriptions types.Int64 `tfsdk:"max_subscriptions"`
+	MaxPayload       types.Int64 `tfsdk:"max_payload"`
+	MaxData          types.Int64 `tfsdk:"max_data"`
+	MaxImports       types.Int64 `tfsdk:"max_imports"`
+	MaxExports       types.Int64 `tfsdk:"max_exports"`
+
+	JetStreamLimits       *jetStreamLimitsModel           `tfsdk:"jetstream_limits"`
+	JetStreamTieredLimits map[string]jetStreamLimitsModel `tfsdk:"jetstream_tiered_limits"`
+
+	ScopedSigningKeys map[string]scopedSigningKeyModel `tfsdk:"scoped_signing_keys"`
+	AuthCallout       *authCalloutModel                `tfsdk:"auth_callout"`
+
+	Exports     []accountExportModel    `tfsdk:"exports"`
+	Imports     []accountImportModel    `tfsdk:"imports"`
+	Revocations map[string]types.String `tfsdk:"revocations"`
+
+	JWT types.String `tfsdk:"jwt"`
+}
+
+type jetStreamLimitsModel struct {
+	MemoryStorage        types.Int64 `tfsdk:"memory_storage"`
+	DiskStorage          types.Int64 `tfsdk:"disk_storage"`
+	Streams              types.Int64 `tfsdk:"streams"`
+	Consumers            types.Int64 `tfsdk:"consumers"`
+	MaxAckPending        types.Int64 `tfsdk:"max_ack_pending"`
+	MemoryMaxStreamBytes types.Int64 `tfsdk:"memory_max_stream_bytes"`
+	DiskMaxStreamBytes   types.Int64 `tfsdk:"disk_max_stream_bytes"`
+	MaxBytesRequired     types.Bool  `tfsdk:"max_bytes_required"`
+}
+
+type scopedSigningKeyModel struct {
+	Key      types.String          `tfsdk:"key"`
+	Template *userPermissionsModel `tfsdk:"template"`
+}
+
+type authCalloutModel struct {
+	AuthUsers       []types.String `tfsdk:"auth_users"`
+	AllowedAccounts []types.String `tfsdk:"allowed_accounts"`
+	XKey            types.String   `tfsdk:"xkey"`
+}
+
+type accountExportModel struct {
+	Name          types.String `tfsdk:"name"`
+	Subject       types.String `tfsdk:"subject"`
+	Type          types.String `tfsdk:"type"`
+	TokenRequired types.Bool   `tfsdk:"token_required"`
+	ResponseType  types.String `tfsdk:"response_type"`
+}
+
+type accountImportModel struct {
+	Name         types.String `tfsdk:"name"`
+	Subject      types.String `tfsdk:"subject"`
+	Account      types.String `tfsdk:"account"`
+	Type         types.String `tfsdk:"type"`
+	LocalSubject types.String `tfsdk:"local_subject"`
+}
+
+func (r *accountResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account"
+}
+
+func (r *accountResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Account resource, issues an account JWT locally without connecting to the server. The JWT is only signed again when its claims change",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"public_key": schema.StringAttribute{
+				Description: "The public key of the account nkey",
+				Required:    true,
+			},
+			"signing_seed": schema.StringAttribute{
+				Description: "The seed of the operator nkey, or of one of the operator signing keys, to sign the account JWT with",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"signing_keys": schema.ListAttribute{
+				Description: "Public account nkeys that can sign user JWTs on behalf of the account",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"scoped_signing_keys": schema.MapNestedAttribute{
+				Description: "Account signing keys that can only issue users with the permissions and limits of their template, keyed by role",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Description: "The public account nkey of the scope",
+							Required:    true,
+						},
+						"template": schema.SingleNestedAttribute{
+							Description: "The permissions and limits of the users issued under the scope. Subjects can hold the {{name()}}, {{subject()}}, {{account-name()}}, {{account-subject()}}, {{tag(<name>)}} and {{account-tag(<name>)}} templates, replaced by the values of the user or the account when the user connects",
+							Optional:    true,
+							Attributes:  userPermissionAttributes(permissionTemplateValidator{}),
+						},
+					},
+				},
+			},
+			"auth_callout": schema.SingleNestedAttribute{
+				Description: "Delegates the authentication of the users of the account to an auth callout service. Auth users and allowed accounts must be nats_user and nats_account resources of the configuration, referenced by their public_key so that they are planned first",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"auth_users": schema.ListAttribute{
+						Description: "Public keys of the users the auth callout service connects as, bypassing the callout",
+						ElementType: types.StringType,
+						Required:    true,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+							listvalidator.ValueStringsAre(publicKeyValidator{keyType: "user"}),
+						},
+					},
+					"allowed_accounts": schema.ListAttribute{
+						Description: "Public keys of the accounts the service can place users in, * for any account. Defaults to the account itself",
+						ElementType: types.StringType,
+						Optional:    true,
+						Validators: []validator.List{
+							listvalidator.ValueStringsAre(stringvalidator.Any(stringvalidator.OneOf("*"), publicKeyValidator{keyType: "account"})),
+						},
+					},
+					"xkey": schema.StringAttribute{
+						Description: "Public curve nkey the server encrypts the requests to the service with",
+						Optional:    true,
+						Validators:  []validator.String{publicKeyValidator{keyType: "curve"}},
+					},
+				},
+			},
+			"max_connections":   accountLimitAttribute("Maximum number of client connections"),
+			"max_leaf_nodes":    accountLimitAttribute("Maximum number of leaf node connections"),
+			"max_subscriptions": accountLimitAttribute("Maximum number of subscriptions"),
+			"max_payload":       accountLimitAttribute("Maximum message payload, in bytes"),
+			"max_data":          accountLimitAttribute("Maximum number of bytes in flight"),
+			"max_imports":       accountLimitAttribute("Maximum number of imports"),
+			"max_exports":       accountLimitAttribute("Maximum number of exports"),
+			"jetstream_limits": schema.SingleNestedAttribute{
+				Description: "Account wide JetStream limits. JetStream is disabled for the account unless these or jetstream_tiered_limits are set",
+				Optional:    true,
+				Attributes:  jetStreamLimitsAttributes(),
+			},
+			"jetstream_tiered_limits": schema.MapNestedAttribute{
+				Description: "JetStream limits per replication tier, e.g. R1 and R3",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: jetStreamLimitsAttributes(),
+				},
+				Validators: []validator.Map{
+					mapvalidator.ConflictsWith(path.MatchRoot("jetstream_limits")),
+				},
+			},
+			"exports": schema.ListNestedAttribute{
+				Description: "The streams and services exported by the account",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Optional: true,
+						},
+						"subject": schema.StringAttribute{
+							Description: "The exported subject, wildcards allowed",
+							Required:    true,
+						},
+						"type": schema.StringAttribute{
+							Description: "The export type. Possible values: stream, service.",
+							Required:    true,
+							Validators:  []validator.String{stringvalidator.OneOf("stream", "service")},
+						},
+						"token_required": schema.BoolAttribute{
+							Description: "If true, importing accounts need an activation token",
+							Optional:    true,
+						},
+						"response_type": schema.StringAttribute{
+							Description: "The responses of a service. Possible values: singleton (default), stream, chunked.",
+							Optional:    true,
+							Validators:  []validator.String{stringvalidator.OneOf("singleton", "stream", "chunked")},
+						},
+					},
+				},
+			},
+			"imports": schema.ListNestedAttribute{
+				Description: "The streams and services imported from other accounts",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Optional: true,
+						},
+						"subject": schema.StringAttribute{
+							Description: "The subject exported by the other account",
+							Required:    true,
+						},
+						"account": schema.StringAttribute{
+							Description: "The public key of the exporting account",
+							Required:    true,
+						},
+						"type": schema.StringAttribute{
+							Description: "The import type. Possible values: stream, service.",
+							Required:    true,
+							Validators:  []validator.String{stringvalidator.OneOf("stream", "service")},
+						},
+						"local_subject": schema.StringAttribute{
+							Description: "The subject the import is available on in the account. Defaults to subject",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"revocations": schema.MapAttribute{
+				Description: "Revokes the user JWTs issued before the given time, in RFC3339 format, keyed by user public key. The key * revokes all users",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.ValueStringsAre(rfc3339Validator{}),
+				},
+			},
+			"jwt": schema.StringAttribute{
+				Description: "The encoded account JWT",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func accountLimitAttribute(description string) schema.Int64Attribute {
+	return schema.Int64Attribute{
+		Description: description + ", -1 for unlimited",
+		Optional:    true,
+		Computed:    true,
+		Default:     int64default.StaticInt64(-1),
+		Validators:  []validator.Int64{int64validator.AtLeast(-1)},
+	}
+}
+
+func jetStreamLimitsAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"memory_storage": schema.Int64Attribute{
+			Description: "Maximum bytes stored in memory across all streams, -1 (default) for unlimited",
+			Optional:    true,
+		},
+		"disk_storage": schema.Int64Attribute{
+			Description: "Maximum bytes stored on disk across all streams, -1 (default) for unlimited",
+			Optional:    true,
+		},
+		"streams": schema.Int64Attribute{
+			Description: "Maximum number of streams, -1 (default) for unlimited",
+			Optional:    true,
+		},
+		"consumers": schema.Int64Attribute{
+			Description: "Maximum number of consumers, -1 (default) for unlimited",
+			Optional:    true,
+		},
+		"max_ack_pending": schema.Int64Attribute{
+			Description: "Maximum ack pending of a consumer, -1 (default) for unlimited",
+			Optional:    true,
+		},
+		"memory_max_stream_bytes": schema.Int64Attribute{
+			Description: "Maximum bytes of a memory stream, 0 (default) for unlimited",
+			Optional:    true,
+		},
+		"disk_max_stream_bytes": schema.Int64Attribute{
+			Description: "Maximum bytes of a file stream, 0 (default) for unlimited",
+			Optional:    true,
+		},
+		"max_bytes_required": schema.BoolAttribute{
+			Description: "If true, streams must set max_bytes",
+			Optional:    true,
+		},
+	}
+}
+
+func (r *accountResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.planned = data.planned
+}
+
+func (r *accountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	// 1. Record the account for the auth callouts of the other accounts
+	var publicKey types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("public_key"), &publicKey)...)
+	if !publicKey.IsUnknown() {
+		r.planned.addPublicKey(publicKey.ValueString())
+	}
+	// 2. Check the auth callout references planned users and accounts
+	resp.Diagnostics.Append(r.validateAuthCallout(ctx, req.Plan)...)
+	// 3. Issue the JWT, which can only be issued once every claim is known
+	if resp.Diagnostics.HasError() || !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+	var plan accountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	token, err := nats.IssueAccountJWT(toAccountSpec(plan), plan.SigningSeed.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid account", fmt.Sprintf("Failed to issue account JWT: %s", err))
+		return
+	}
+	state := types.StringNull()
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("jwt"), &state)...)
+	}
+	plan.JWT = planJWT(state, token)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// validateAuthCallout checks the auth users and allowed accounts of the auth
+// callout are nats_user and nats_account resources of the configuration. Keys
+// only known after apply can't be checked, they are warned about.
+func (r *accountResource) validateAuthCallout(ctx context.Context, plan tfsdk.Plan) diag.Diagnostics {
+	var diags diag.Diagnostics
+	attribute := path.Root("auth_callout")
+	var authUsers, allowedAccounts types.List
+	diags.Append(plan.GetAttribute(ctx, attribute.AtName("auth_users"), &authUsers)...)
+	diags.Append(plan.GetAttribute(ctx, attribute.AtName("allowed_accounts"), &allowedAccounts)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if authUsers.IsUnknown() {
+		diags.AddAttributeWarning(attribute.AtName("auth_users"), "Unable to check auth users", "The auth users are only known after apply, they can't be checked against the nats_user resources of the configuration")
+	}
+	for i, user := range authUsers.Elements() {
+		user := user.(types.String)
+		switch {
+		case user.IsUnknown():
+			diags.AddAttributeWarning(attribute.AtName("auth_users").AtListIndex(i), "Unable to check auth user", "The public key is only known after apply, it can't be checked against the nats_user resources of the configuration")
+		case !r.planned.hasPublicKey(user.ValueString()):
+			diags.AddAttributeError(attribute.AtName("auth_users").AtListIndex(i), "Unknown auth user", fmt.Sprintf("No nats_user of the configuration has the public key %s. Reference the public_key of the nats_user so that it is planned before the account", user.ValueString()))
+		}
+	}
+	if allowedAccounts.IsUnknown() {
+		diags.AddAttributeWarning(attribute.AtName("allowed_accounts"), "Unable to check allowed accounts", "The allowed accounts are only known after apply, they can't be checked against the nats_account resources of the configuration")
+	}
+	for i, account := range allowedAccounts.Elements() {
+		account := account.(types.String)
+		switch {
+		case account.IsUnknown():
+			diags.AddAttributeWarning(attribute.AtName("allowed_accounts").AtListIndex(i), "Unable to check allowed account", "The public key is only known after apply, it can't be checked against the nats_account resources of the configuration")
+		case account.ValueString() != "*" && !r.planned.hasPublicKey(account.ValueString()):
+			diags.AddAttributeError(attribute.AtName("allowed_accounts").AtListIndex(i), "Unknown allowed account", fmt.Sprintf("No nats_account of the configuration has the public key %s. Reference the public_key of the nats_account so that it is planned before this account", account.ValueString()))
+		}
+	}
+	return diags
+}
+
+func (r *accountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// 1. Read plan
+	var data accountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Issue the JWT
+	resp.Diagnostics.Append(data.issue()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 3. Write state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *accountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The JWT only lives in the state, there is nothing to refresh
+	var data accountResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *accountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// 1. Read plan
+	var data accountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Issue the JWT, unless the JWT of the state was kept when planning
+	resp.Diagnostics.Append(data.issue()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 3. Write new state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *accountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The JWT only lives in the state, removing the resource from the state is enough
+}
+
+func (data *accountResourceModel) issue() diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !data.JWT.IsUnknown() {
+		return diags
+	}
+	token, err := nats.IssueAccountJWT(toAccountSpec(*data), data.SigningSeed.ValueString())
+	if err != nil {
+		diags.AddError("Invalid account", fmt.Sprintf("Failed to issue account JWT: %s", err))
+		return diags
+	}
+	data.JWT = types.StringValue(token)
+	return diags
+}
+
+func toAccountSpec(data accountResourceModel) nats.AccountSpec {
+	spec := nats.AccountSpec{
+		Name:        data.Name.ValueString(),
+		PublicKey:   data.PublicKey.ValueString(),
+		SigningKeys: convertSlice(data.SigningKeys, (types.String).ValueString),
+		Limits: nats.AccountLimits{
+			MaxConnections:   data.MaxConnections.ValueInt64(),
+			MaxLeafNodes:     data.MaxLeafNodes.ValueInt64(),
+			MaxSubscriptions: data.MaxSubscriptions.ValueInt64(),
+			MaxPayload:       data.MaxPayload.ValueInt64(),
+			MaxData:          data.MaxData.ValueInt64(),
+			MaxImports:       data.MaxImports.ValueInt64(),
+			MaxExports:       data.MaxExports.ValueInt64(),
+		},
+	}
+	if data.JetStreamLimits != nil {
+		limits := toJetStreamLimits(*data.JetStreamLimits)
+		spec.JetStream = &limits
+	}
+	if len(data.JetStreamTieredLimits) > 0 {
+		spec.JetStreamTiers = make(map[string]nats.JetStreamLimits, len(data.JetStreamTieredLimits))
+		for tier, limits := range data.JetStreamTieredLimits {
+			spec.JetStreamTiers[tier] = toJetStreamLimits(limits)
+		}
+	}
+	for _, export := range data.Exports {
+		spec.Exports = append(spec.Exports, nats.Export{
+			Name:          export.Name.ValueString(),
+			Subject:       export.Subject.ValueString(),
+			Type:          export.Type.ValueString(),
+			TokenRequired: export.TokenRequired.ValueBool(),
+			ResponseType:  export.ResponseType.ValueString(),
+		})
+	}
+	for _, imp := range data.Imports {
+		spec.Imports = append(spec.Imports, nats.Import{
+			Name:         imp.Name.ValueString(),
+			Subject:      imp.Subject.ValueString(),
+			Account:      imp.Account.ValueString(),
+			Type:         imp.Type.ValueString(),
+			LocalSubject: imp.LocalSubject.ValueString(),
+		})
+	}
+	for role, scope := range data.ScopedSigningKeys {
+		var template userPermissionsModel
+		if scope.Template != nil {
+			template = *scope.Template
+		}
+		spec.ScopedSigningKeys = append(spec.ScopedSigningKeys, nats.ScopedSigningKey{
+			Role:     role,
+			Key:      scope.Key.ValueString(),
+			Template: toUserPermissions(template),
+		})
+	}
+	if data.AuthCallout != nil {
+		spec.AuthCallout = &nats.AuthCallout{
+			AuthUsers:       convertSlice(data.AuthCallout.AuthUsers, (types.String).ValueString),
+			AllowedAccounts: convertSlice(data.AuthCallout.AllowedAccounts, (types.String).ValueString),
+			XKey:            data.AuthCallout.XKey.ValueString(),
+		}
+	}
+	if len(data.Revocations) > 0 {
+		spec.Revocations = make(map[string]time.Time, len(data.Revocations))
+		for publicKey, before := range data.Revocations {
+			// The value is validated as RFC3339
+			spec.Revocations[publicKey], _ = time.Parse(time.RFC3339, before.ValueString())
+		}
+	}
+	return spec
+}
+
+func toJetStreamLimits(data jetStreamLimitsModel) nats.JetStreamLimits {
+	return nats.JetStreamLimits{
+		MemoryStorage:        int64OrDefault(data.MemoryStorage, -1),
+		DiskStorage:          int64OrDefault(data.DiskStorage, -1),
+		Streams:              int64OrDefault(data.Streams, -1),
+		Consumer:             int64OrDefault(data.Consumers, -1),
+		MaxAckPending:        int64OrDefault(data.MaxAckPending, -1),
+		MemoryMaxStreamBytes: data.MemoryMaxStreamBytes.ValueInt64(),
+		DiskMaxStreamBytes:   data.DiskMaxStreamBytes.ValueInt64(),
+		MaxBytesRequired:     data.MaxBytesRequired.ValueBool(),
+	}
+}
+
+func int64OrDefault(value types.Int64, defaultValue int64) int64 {
+	if value.IsNull() {
+		return defaultValue
+	}
+	return value.ValueInt64()
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"regexp"
+	"terraform-provider-nats/internal/nats"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var timeOfDayRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]:[0-5][0-9]$`)
+
+// planJWT returns the JWT of the state if it holds the same claims as the
+// newly issued one, so that JWTs are only signed again when their claims
+// change. Otherwise the JWT is unknown until applying signs it, as every
+// signing gives a different token.
+func planJWT(state types.String, issued string) types.String {
+	if !state.IsNull() && !state.IsUnknown() && nats.SameClaims(state.ValueString(), issued) {
+		return state
+	}
+	return types.StringUnknown()
+}
+
+// userPermissionsModel maps the attributes of userPermissionAttributes.
+type userPermissionsModel struct {
+	PubAllow            []types.String           `tfsdk:"pub_allow"`
+	PubDeny             []types.String           `tfsdk:"pub_deny"`
+	SubAllow            []types.String           `tfsdk:"sub_allow"`
+	SubDeny             []types.String           `tfsdk:"sub_deny"`
+	ResponsePermissions *responsePermissionModel `tfsdk:"response_permissions"`
+	ConnectionTypes     []types.String           `tfsdk:"connection_types"`
+	SourceNetworks      []types.String           `tfsdk:"source_networks"`
+	TimeRestrictions    []timeRangeModel         `tfsdk:"time_restrictions"`
+	Locale              types.String             `tfsdk:"locale"`
+	BearerToken         types.Bool               `tfsdk:"bearer_token"`
+	MaxSubscriptions    types.Int64              `tfsdk:"max_subscriptions"`
+	MaxData             types.Int64              `tfsdk:"max_data"`
+	MaxPayload          types.Int64              `tfsdk:"max_payload"`
+}
+
+type responsePermissionModel struct {
+	MaxMsgs types.Int64 `tfsdk:"max_msgs"`
+	TTL     types.Int64 `tfsdk:"ttl"`
+}
+
+type timeRangeModel struct {
+	Start types.String `tfsdk:"start"`
+	End   types.String `tfsdk:"end"`
+}
+
+// userPermissionAttributes returns the permission and limit attributes of
+// users, shared by nats_user and the templates of scoped signing keys. The
+// subject validators apply to the pub and sub subjects.
+func userPermissionAttributes(subjectValidators ...validator.String) map[string]schema.Attribute {
+	timeOfDay := stringvalidator.RegexMatches(timeOfDayRegex, "must be a time of day in the HH:MM:SS format")
+	subjects := func(description string) schema.ListAttribute {
+		attribute := schema.ListAttribute{
+			Description: description,
+			ElementType: types.StringType,
+			Optional:    true,
+		}
+		if len(subjectValidators) > 0 {
+			attribute.Validators = []validator.List{listvalidator.ValueStringsAre(subjectValidators...)}
+		}
+		return attribute
+	}
+	return map[string]schema.Attribute{
+		"pub_allow": subjects("Subjects the user is allowed to publish to, wildcards allowed. Defaults to all subjects"),
+		"pub_deny":  subjects("Subjects the user is not allowed to publish to, wildcards allowed"),
+		"sub_allow": subjects("Subjects the user is allowed to subscribe to, wildcards allowed. Defaults to all subjects"),
+		"sub_deny":  subjects("Subjects the user is not allowed to subscribe to, wildcards allowed"),
+		"response_permissions": schema.SingleNestedAttribute{
+			Description: "Allows the user to reply to the requests it receives, regardless of the publish permissions",
+			Optional:    true,
+			Attributes: map[string]schema.Attribute{
+				"max_msgs": schema.Int64Attribute{
+					Description: "Maximum number of replies to a request, -1 for unlimited",
+					Required:    true,
+					Validators:  []validator.Int64{int64validator.AtLeast(-1)},
+				},
+				"ttl": schema.Int64Attribute{
+					Description: "Time, in nanoseconds, the user can reply to a request for, 0 for unlimited",
+					Optional:    true,
+					Validators:  []validator.Int64{int64validator.AtLeast(0)},
+				},
+			},
+		},
+		"connection_types": schema.ListAttribute{
+			Description: "Connection types the user can connect with. Defaults to all types. Possible values: STANDARD, WEBSOCKET, LEAFNODE, LEAFNODE_WS, MQTT, MQTT_WS.",
+			ElementType: types.StringType,
+			Optional:    true,
+			Validators: []validator.List{
+				listvalidator.ValueStringsAre(stringvalidator.OneOf("STANDARD", "WEBSOCKET", "LEAFNODE", "LEAFNODE_WS", "MQTT", "MQTT_WS")),
+			},
+		},
+		"source_networks": schema.ListAttribute{
+			Description: "CIDRs the user can connect from. Defaults to any network",
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+		"time_restrictions": schema.ListNestedAttribute{
+			Description: "Times of day the user can connect at. Defaults to any time",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"start": schema.StringAttribute{
+						Description: "Start of the range, in the HH:MM:SS format",
+						Required:    true,
+						Validators:  []validator.String{timeOfDay},
+					},
+					"end": schema.StringAttribute{
+						Description: "End of the range, in the HH:MM:SS format",
+						Required:    true,
+						Validators:  []validator.String{timeOfDay},
+					},
+				},
+			},
+		},
+		"locale": schema.StringAttribute{
+			Description: "The time zone of time_restrictions, e.g. Europe/Berlin. Defaults to the server time zone",
+			Optional:    true,
+		},
+		"bearer_token": schema.BoolAttribute{
+			Description: "If true, the server doesn't require the user to sign a nonce with its seed, the JWT alone is enough to connect",
+			Optional:    true,
+		},
+		"max_subscriptions": schema.Int64Attribute{
+			Description: "Maximum number of subscriptions, -1 (default) for unlimited",
+			Optional:    true,
+			Validators:  []validator.Int64{int64validator.AtLeast(-1)},
+		},
+		"max_data": schema.Int64Attribute{
+			Description: "Maximum number of bytes in flight, -1 (default) for unlimited",
+			Optional:    true,
+			Validators:  []validator.Int64{int64validator.AtLeast(-1)},
+		},
+		"max_payload": schema.Int64Attribute{
+			Description: "Maximum message payload, in bytes, -1 (default) for unlimited",
+			Optional:    true,
+			Validators:  []validator.Int64{int64validator.AtLeast(-1)},
+		},
+	}
+}
+
+func toUserPermissions(data userPermissionsModel) nats.UserPermissions {
+	permissions := nats.UserPermissions{
+		PubAllow:         convertSlice(data.PubAllow, (types.String).ValueString),
+		PubDeny:          convertSlice(data.PubDeny, (types.String).ValueString),
+		SubAllow:         convertSlice(data.SubAllow, (types.String).ValueString),
+		SubDeny:          convertSlice(data.SubDeny, (types.String).ValueString),
+		ConnectionTypes:  convertSlice(data.ConnectionTypes, (types.String).ValueString),
+		SourceNetworks:   convertSlice(data.SourceNetworks, (types.String).ValueString),
+		Locale:           data.Locale.ValueString(),
+		BearerToken:      data.BearerToken.ValueBool(),
+		MaxSubscriptions: int64OrDefault(data.MaxSubscriptions, -1),
+		MaxData:          int64OrDefault(data.MaxData, -1),
+		MaxPayload:       int64OrDefault(data.MaxPayload, -1),
+	}
+	if data.ResponsePermissions != nil {
+		permissions.Responses = &nats.ResponsePermission{
+			MaxMsgs: int(data.ResponsePermissions.MaxMsgs.ValueInt64()),
+			TTL:     time.Duration(data.ResponsePermissions.TTL.ValueInt64()),
+		}
+	}
+	for _, timeRange := range data.TimeRestrictions {
+		permissions.TimeRanges = append(permissions.TimeRanges, nats.TimeRange{
+			Start: timeRange.Start.ValueString(),
+			End:   timeRange.End.ValueString(),
+		})
+	}
+	return permissions
+}
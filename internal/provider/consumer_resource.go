@@ -4,13 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"terraform-provider-nats/internal/nats"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -19,18 +26,26 @@ import (
 
 var _ resource.ResourceWithConfigure = &consumerResource{}
 var _ resource.ResourceWithImportState = &consumerResource{}
+var _ resource.ResourceWithModifyPlan = &consumerResource{}
+
+var priorityGroupRegex = regexp.MustCompile(`^[a-zA-Z0-9/_=-]{1,16}$`)
 
 func NewConsumerResource() resource.Resource {
 	return &consumerResource{}
 }
 
 type consumerResource struct {
-	client nats.Client
+	client  nats.Client
+	planned *plannedResources
 }
 
 type consumerResourceModel struct {
 	StreamName types.String `tfsdk:"stream_name"`
 	Name       types.String `tfsdk:"name"`
+	Domain     types.String `tfsdk:"domain"`
+
+	Durable           types.Bool  `tfsdk:"durable"`
+	InactiveThreshold types.Int64 `tfsdk:"inactive_threshold"`
 
 	Mode           types.String   `tfsdk:"mode"`
 	DeliverPolicy  types.String   `tfsdk:"deliver_policy"`
@@ -40,6 +55,15 @@ type consumerResourceModel struct {
 	// Push-Specific
 	DeliverSubject types.String `tfsdk:"deliver_subject"`
 	DeliverGroup   types.String `tfsdk:"deliver_group"`
+
+	// Pull-Specific
+	PriorityGroups  []types.String `tfsdk:"priority_groups"`
+	PriorityPolicy  types.String   `tfsdk:"priority_policy"`
+	PriorityTimeout types.Int64    `tfsdk:"priority_timeout"`
+
+	PausedUntil    types.String `tfsdk:"paused_until"`
+	Paused         types.Bool   `tfsdk:"paused"`
+	PauseRemaining types.Int64  `tfsdk:"pause_remaining"`
 }
 
 func (r *consumerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -56,6 +80,23 @@ func (r *consumerResource) Schema(ctx context.Context, req resource.SchemaReques
 			"name": schema.StringAttribute{
 				Required: true,
 			},
+			"domain": schema.StringAttribute{
+				Description: "The JetStream domain of the stream. Defaults to the provider's jetstream_domain",
+				Optional:    true,
+			},
+			"durable": schema.BoolAttribute{
+				Description: "Whether the consumer is durable. An ephemeral consumer is removed by the server once it has no clients for inactive_threshold, and is created again on the next apply",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+			"inactive_threshold": schema.Int64Attribute{
+				Description: "How long the consumer can go without clients before the server removes it, expressed in nanoseconds, 0 to never remove it. Must be set if durable = false",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(0),
+				Validators:  []validator.Int64{int64validator.AtLeast(0)},
+			},
 			"mode": schema.StringAttribute{
 				Description: "The consumer mode. Possible values: push, pull.",
 				Required:    true,
@@ -76,7 +117,7 @@ func (r *consumerResource) Schema(ctx context.Context, req resource.SchemaReques
 				Validators:  []validator.String{stringvalidator.OneOf("none", "all", "explicit")},
 			},
 			"filter_subjects": schema.ListAttribute{
-				Description: "A set of subjects that overlap with the subjects bound to the stream to filter delivery to subscribers. Default is all stream subjects (no filtering).",
+				Description: "A set of subjects that overlap with the subjects bound to the stream to filter delivery to subscribers. Default is all stream subjects (no filtering). Filters must not overlap each other and are checked against the stream subjects when planning. The single `filter_subject` of consumers created by older tooling is read into this list.",
 				ElementType: types.StringType,
 				Optional:    true,
 				Computed:    true,
@@ -95,6 +136,44 @@ func (r *consumerResource) Schema(ctx context.Context, req resource.SchemaReques
 				Computed:    true,
 				Default:     stringdefault.StaticString(""),
 			},
+			// Pull-specific
+			"priority_groups": schema.ListAttribute{
+				Description: "The priority groups that clients pulling from the consumer join, needed by priority_policy. Used only if mode = pull",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, nil)),
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.RegexMatches(priorityGroupRegex, "must be 1 to 16 letters, digits or one of /_=-")),
+				},
+			},
+			"priority_policy": schema.StringAttribute{
+				Description: "How messages are shared between the clients of a priority group. Possible values: none (default), overflow, pinned_client. Used only if mode = pull. A policy of a newer server that the provider doesn't know is read as unknown",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("none"),
+				Validators:  []validator.String{stringvalidator.OneOf("none", "overflow", "pinned_client")},
+			},
+			"priority_timeout": schema.Int64Attribute{
+				Description: "How long a pinned client can go without pulling before another client is pinned, expressed in nanoseconds. Used only if priority_policy = pinned_client",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(0),
+				Validators:  []validator.Int64{int64validator.AtLeast(0)},
+			},
+			"paused_until": schema.StringAttribute{
+				Description: "Pause the consumer until this time, in RFC3339 format. Removing it resumes the consumer. Requires nats-server 2.11 or later",
+				Optional:    true,
+				Validators:  []validator.String{rfc3339Validator{}},
+			},
+			"paused": schema.BoolAttribute{
+				Description: "Whether the consumer is paused",
+				Computed:    true,
+			},
+			"pause_remaining": schema.Int64Attribute{
+				Description: "Time left until the consumer resumes, in nanoseconds",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -104,16 +183,18 @@ func (r *consumerResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(nats.Client)
+	data, ok := req.ProviderData.(*resourceData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected nats.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = data.Client
+	r.planned = data.planned
+	resp.Diagnostics.Append(data.checkServer()...)
 }
 
 func (r *consumerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -129,13 +210,14 @@ func (r *consumerResource) Create(ctx context.Context, req resource.CreateReques
 		resp.Diagnostics.AddError("Validation error", err.Error())
 		return
 	}
-	consumerInfo, err := r.client.CreateConsumer(data.StreamName.ValueString(), consumerConfig)
+	// paused_until is part of the config, so the consumer doesn't deliver before the pause
+	consumerInfo, err := r.client.WithDomain(data.Domain.ValueString()).CreateConsumer(data.StreamName.ValueString(), consumerConfig)
 	if err != nil {
 		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to create consumer: %s", err))
 		return
 	}
 	// 3. Write state
-	data = fromConsumerInfo(consumerInfo)
+	data = fromConsumerInfo(consumerInfo).withLocalAttributes(data)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -147,10 +229,14 @@ func (r *consumerResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 	// 2. Get the resource
-	consumerInfo, err := r.client.GetConsumer(data.StreamName.ValueString(), data.Name.ValueString())
+	consumerInfo, err := r.client.WithDomain(data.Domain.ValueString()).GetConsumer(data.StreamName.ValueString(), data.Name.ValueString())
 	if err != nil {
 		if errors.Is(err, nats.ErrNotFound) {
-			resp.Diagnostics.AddWarning("Resource not found", "couldn't find the consumer, possibly deleted outside terraform")
+			if !data.Durable.IsNull() && !data.Durable.ValueBool() {
+				resp.Diagnostics.AddWarning("Ephemeral consumer expired", fmt.Sprintf("The consumer %q was removed by the server after being inactive for inactive_threshold, it will be created again", data.Name.ValueString()))
+			} else {
+				resp.Diagnostics.AddWarning("Resource not found", "couldn't find the consumer, possibly deleted outside terraform")
+			}
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -158,7 +244,7 @@ func (r *consumerResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 	// 3. Write new state
-	data = fromConsumerInfo(consumerInfo)
+	data = fromConsumerInfo(consumerInfo).withLocalAttributes(data)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -182,19 +268,51 @@ func (r *consumerResource) Update(ctx context.Context, req resource.UpdateReques
 		)
 		return
 	}
+	if plan.Durable != state.Durable {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("durable"),
+			"Cannot change consumer durability",
+			"A consumer cannot be made durable or ephemeral once created. If you wish to change it, you must create a new consumer.",
+		)
+		return
+	}
+	if plan.Domain != state.Domain {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("domain"),
+			"Cannot change consumer domain",
+			"The consumer lives in the JetStream domain of its stream. If you wish to change the domain, you must create a new consumer.",
+		)
+		return
+	}
 	// 3. Update resource
 	consumerConfig, err := toConsumerConfig(plan)
 	if err != nil {
 		resp.Diagnostics.AddError("Validation error", err.Error())
 		return
 	}
-	consumerInfo, err := r.client.UpdateConsumer(plan.StreamName.ValueString(), consumerConfig)
+	// The update keeps the current pause, a new one goes through the pause API
+	consumerConfig.PauseUntil, _ = parsePausedUntil(state.PausedUntil)
+	client := r.client.WithDomain(plan.Domain.ValueString())
+	consumerInfo, err := client.UpdateConsumer(plan.StreamName.ValueString(), consumerConfig)
 	if err != nil {
 		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to update consumer: %s", err))
 		return
 	}
-	// 4. Write new state
-	state = fromConsumerInfo(consumerInfo)
+	// 4. Pause or resume the consumer
+	if !samePausedUntil(plan.PausedUntil, state.PausedUntil) {
+		pauseUntil, _ := parsePausedUntil(plan.PausedUntil)
+		if err := client.PauseConsumer(plan.StreamName.ValueString(), plan.Name.ValueString(), pauseUntil); err != nil {
+			resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to pause consumer: %s", err))
+			return
+		}
+		consumerInfo, err = client.GetConsumer(plan.StreamName.ValueString(), plan.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to read consumer: %s", err))
+			return
+		}
+	}
+	// 5. Write new state
+	state = fromConsumerInfo(consumerInfo).withLocalAttributes(plan)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -206,13 +324,105 @@ func (r *consumerResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 	// 2. Delete the resource
-	err := r.client.DeleteConsumer(state.StreamName.ValueString(), state.Name.ValueString())
-	if err != nil {
+	err := r.client.WithDomain(state.Domain.ValueString()).DeleteConsumer(state.StreamName.ValueString(), state.Name.ValueString())
+	// An ephemeral consumer may have expired already
+	if err != nil && !errors.Is(err, nats.ErrNotFound) {
 		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to delete consumer: %s", err))
 		return
 	}
 }
 
+func (r *consumerResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+	// 1. Read the planned filters
+	var streamName, domain types.String
+	var filterList types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("stream_name"), &streamName)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("domain"), &domain)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("filter_subjects"), &filterList)...)
+	if resp.Diagnostics.HasError() || filterList.IsUnknown() || len(filterList.Elements()) == 0 {
+		return
+	}
+	filters := make([]string, 0, len(filterList.Elements()))
+	for _, element := range filterList.Elements() {
+		filter, ok := element.(types.String)
+		if !ok || filter.IsUnknown() {
+			return
+		}
+		filters = append(filters, filter.ValueString())
+	}
+	// 2. Check the filters don't overlap each other
+	for i, filter := range filters {
+		for _, other := range filters[i+1:] {
+			if nats.SubjectsOverlap(filter, other) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("filter_subjects"),
+					"Overlapping filter subjects",
+					fmt.Sprintf("Filter %q overlaps with filter %q, the filters of a consumer can't overlap.", filter, other),
+				)
+			}
+		}
+	}
+	if resp.Diagnostics.HasError() || streamName.IsUnknown() || domain.IsUnknown() {
+		return
+	}
+	// 3. Check the filters against the stream subjects, as planned or else on the server
+	subjects, known, planned := r.planned.plannedStream(domain.ValueString(), streamName.ValueString())
+	if planned && !known {
+		// The stream subjects are only known after apply
+		return
+	}
+	if !planned {
+		streamInfo, err := r.client.WithDomain(domain.ValueString()).GetStream(streamName.ValueString(), "")
+		// The stream may be created in the same apply, after being planned
+		if errors.Is(err, nats.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddWarning("Unable to check filter subjects", fmt.Sprintf("Failed to read stream: %s", err))
+			return
+		}
+		subjects = streamInfo.Config.Subjects
+	}
+	// Mirrors and streams with only sources have no subjects to check against
+	if len(subjects) == 0 {
+		return
+	}
+	resp.Diagnostics.Append(checkFilterSubjects(streamName.ValueString(), subjects, filters)...)
+}
+
+// checkFilterSubjects checks every filter is within the stream subjects. A
+// filter matching none of them is an error, as the consumer would never
+// receive a message. One only partly within them is a warning.
+func checkFilterSubjects(streamName string, subjects, filters []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, filter := range filters {
+		var within, overlaps bool
+		for _, subject := range subjects {
+			within = within || nats.SubjectMatches(filter, subject)
+			overlaps = overlaps || nats.SubjectsOverlap(filter, subject)
+		}
+		switch {
+		case !overlaps:
+			diags.AddAttributeError(
+				path.Root("filter_subjects"),
+				"Filter subject outside the stream",
+				fmt.Sprintf("Filter %q matches none of the subjects of stream %s (%s), the consumer would never receive a message.", filter, streamName, strings.Join(subjects, ", ")),
+			)
+		case !within:
+			diags.AddAttributeWarning(
+				path.Root("filter_subjects"),
+				"Filter subject partly outside the stream",
+				fmt.Sprintf("Filter %q is not within the subjects of stream %s (%s), only the messages of the stream subjects are delivered.", filter, streamName, strings.Join(subjects, ", ")),
+			)
+		}
+	}
+	return diags
+}
+
 func (r *consumerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp) // TODO(sagheer): id is name + stream_name
 
@@ -229,32 +439,94 @@ func toConsumerConfig(data consumerResourceModel) (nats.ConsumerConfig, error) {
 	if err := validateMode(data); err != nil {
 		return nats.ConsumerConfig{}, err
 	}
-	return nats.ConsumerConfig{
-		Name:           data.Name.ValueString(),
-		Durable:        data.Name.ValueString(),
-		DeliverPolicy:  nats.ToDeliverPolicy(data.DeliverPolicy.ValueString()),
-		AckPolicy:      nats.ToAckPolicy(data.AckPolicy.ValueString()),
-		FilterSubjects: convertSlice(data.FilterSubjects, (types.String).ValueString),
-		DeliverSubject: data.DeliverSubject.ValueString(),
-		DeliverGroup:   data.DeliverGroup.ValueString(),
-	}, nil
+	if err := validatePriority(data); err != nil {
+		return nats.ConsumerConfig{}, err
+	}
+	if !data.Durable.ValueBool() && data.InactiveThreshold.ValueInt64() == 0 {
+		return nats.ConsumerConfig{}, fmt.Errorf("Attribute 'inactive_threshold' must be set if 'durable' is false")
+	}
+	pauseUntil, err := parsePausedUntil(data.PausedUntil)
+	if err != nil {
+		return nats.ConsumerConfig{}, err
+	}
+	consumerConfig := nats.ConsumerConfig{PauseUntil: pauseUntil}
+	consumerConfig.Name = data.Name.ValueString()
+	if data.Durable.ValueBool() {
+		consumerConfig.Durable = data.Name.ValueString()
+	}
+	consumerConfig.InactiveThreshold = time.Duration(data.InactiveThreshold.ValueInt64())
+	consumerConfig.DeliverPolicy = nats.ToDeliverPolicy(data.DeliverPolicy.ValueString())
+	consumerConfig.AckPolicy = nats.ToAckPolicy(data.AckPolicy.ValueString())
+	consumerConfig.FilterSubjects = convertSlice(data.FilterSubjects, (types.String).ValueString)
+	consumerConfig.DeliverSubject = data.DeliverSubject.ValueString()
+	consumerConfig.DeliverGroup = data.DeliverGroup.ValueString()
+	consumerConfig.PriorityGroups = convertSlice(data.PriorityGroups, (types.String).ValueString)
+	consumerConfig.PriorityPolicy = nats.ToPriorityPolicy(data.PriorityPolicy.ValueString())
+	consumerConfig.PriorityTimeout = time.Duration(data.PriorityTimeout.ValueInt64())
+	return consumerConfig, nil
 }
 
 func fromConsumerInfo(consumerInfo nats.ConsumerInfo) consumerResourceModel {
-	var mode = "pull"
-	if consumerInfo.Config.DeliverSubject != "" {
-		mode = "push"
+	pausedUntil := types.StringNull()
+	if consumerInfo.Config.PauseUntil != nil {
+		pausedUntil = timestampValue(*consumerInfo.Config.PauseUntil)
 	}
 	return consumerResourceModel{
-		StreamName:     types.StringValue(consumerInfo.Stream),
-		Name:           types.StringValue(consumerInfo.Name),
-		Mode:           types.StringValue(mode),
-		DeliverPolicy:  types.StringValue(nats.FromDeliverPolicy(consumerInfo.Config.DeliverPolicy)),
-		AckPolicy:      types.StringValue(nats.FromAckPolicy(consumerInfo.Config.AckPolicy)),
-		FilterSubjects: convertSlice(consumerInfo.Config.FilterSubjects, types.StringValue),
-		DeliverSubject: types.StringValue(consumerInfo.Config.DeliverSubject),
-		DeliverGroup:   types.StringValue(consumerInfo.Config.DeliverGroup),
+		StreamName:        types.StringValue(consumerInfo.Stream),
+		Name:              types.StringValue(consumerInfo.Name),
+		Durable:           types.BoolValue(consumerInfo.Config.Durable != ""),
+		InactiveThreshold: types.Int64Value(int64(consumerInfo.Config.InactiveThreshold)),
+		Mode:              types.StringValue(consumerMode(consumerInfo)),
+		DeliverPolicy:     types.StringValue(nats.FromDeliverPolicy(consumerInfo.Config.DeliverPolicy)),
+		AckPolicy:         types.StringValue(nats.FromAckPolicy(consumerInfo.Config.AckPolicy)),
+		FilterSubjects:    convertSlice(consumerInfo.Config.Filters(), types.StringValue),
+		DeliverSubject:    types.StringValue(consumerInfo.Config.DeliverSubject),
+		DeliverGroup:      types.StringValue(consumerInfo.Config.DeliverGroup),
+		PriorityGroups:    convertSlice(consumerInfo.Config.PriorityGroups, types.StringValue),
+		PriorityPolicy:    types.StringValue(nats.FromPriorityPolicy(consumerInfo.Config.PriorityPolicy)),
+		PriorityTimeout:   types.Int64Value(int64(consumerInfo.Config.PriorityTimeout)),
+		PausedUntil:       pausedUntil,
+		Paused:            types.BoolValue(consumerInfo.Paused),
+		PauseRemaining:    types.Int64Value(int64(consumerInfo.PauseRemaining)),
+	}
+}
+
+// withLocalAttributes carries over the attributes that are not part of the
+// consumer info. paused_until is kept as configured if the server reports the
+// same deadline, which it stores in UTC.
+func (data consumerResourceModel) withLocalAttributes(from consumerResourceModel) consumerResourceModel {
+	data.Domain = from.Domain
+	if samePausedUntil(data.PausedUntil, from.PausedUntil) {
+		data.PausedUntil = from.PausedUntil
+	}
+	return data
+}
+
+func parsePausedUntil(value types.String) (*time.Time, error) {
+	if value.IsNull() || value.IsUnknown() {
+		return nil, nil
+	}
+	pauseUntil, err := time.Parse(time.RFC3339, value.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("Attribute 'paused_until' must be in RFC3339 format: %w", err)
+	}
+	return &pauseUntil, nil
+}
+
+func samePausedUntil(a, b types.String) bool {
+	x, errX := parsePausedUntil(a)
+	y, errY := parsePausedUntil(b)
+	if errX != nil || errY != nil || x == nil || y == nil {
+		return a.Equal(b)
+	}
+	return x.Equal(*y)
+}
+
+func consumerMode(consumerInfo nats.ConsumerInfo) string {
+	if consumerInfo.Config.DeliverSubject != "" {
+		return "push"
 	}
+	return "pull"
 }
 
 func validateMode(data consumerResourceModel) error {
@@ -266,3 +538,17 @@ func validateMode(data consumerResourceModel) error {
 	}
 	return nil
 }
+
+func validatePriority(data consumerResourceModel) error {
+	policy := data.PriorityPolicy.ValueString()
+	if data.Mode.ValueString() == "push" && (len(data.PriorityGroups) > 0 || policy != "none") {
+		return fmt.Errorf("Attributes 'priority_groups' and 'priority_policy' must not be set if 'mode' is 'push'")
+	}
+	if policy != "none" && len(data.PriorityGroups) == 0 {
+		return fmt.Errorf("Attribute 'priority_groups' must be set if 'priority_policy' is %q", policy)
+	}
+	if policy != "pinned_client" && data.PriorityTimeout.ValueInt64() != 0 {
+		return fmt.Errorf("Attribute 'priority_timeout' must not be set if 'priority_policy' is not 'pinned_client'")
+	}
+	return nil
+}
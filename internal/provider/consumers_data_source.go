@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &consumersDataSource{}
+
+// NewConsumersDataSource creates a new consumers datasource.
+func NewConsumersDataSource() datasource.DataSource {
+	return &consumersDataSource{}
+}
+
+type consumersDataSource struct {
+	client nats.Client
+}
+
+type consumersDataSourceModel struct {
+	StreamName types.String `tfsdk:"stream_name"`
+	Domain     types.String `tfsdk:"domain"`
+
+	Consumers []consumerSummaryModel `tfsdk:"consumers"`
+}
+
+type consumerSummaryModel struct {
+	Name           types.String   `tfsdk:"name"`
+	Mode           types.String   `tfsdk:"mode"`
+	FilterSubjects []types.String `tfsdk:"filter_subjects"`
+	AckPolicy      types.String   `tfsdk:"ack_policy"`
+	NumPending     types.Int64    `tfsdk:"num_pending"`
+	NumAckPending  types.Int64    `tfsdk:"num_ack_pending"`
+	NumRedelivered types.Int64    `tfsdk:"num_redelivered"`
+	NumWaiting     types.Int64    `tfsdk:"num_waiting"`
+}
+
+func (d *consumersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_consumers"
+}
+
+func (d *consumersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Consumers data source, lists the consumers of a stream",
+		Attributes: map[string]schema.Attribute{
+			"stream_name": schema.StringAttribute{
+				Required: true,
+			},
+			"domain": schema.StringAttribute{
+				Description: "The JetStream domain of the stream. Defaults to the provider's jetstream_domain",
+				Optional:    true,
+			},
+			"consumers": schema.ListNestedAttribute{
+				Description: "The consumers of the stream, sorted by name",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"mode": schema.StringAttribute{
+							Description: "The consumer mode. Possible values: push, pull.",
+							Computed:    true,
+						},
+						"filter_subjects": schema.ListAttribute{
+							Description: "The subjects the consumer filters delivery on",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"ack_policy": schema.StringAttribute{
+							Description: "The requirement of client acknowledgements. Possible values: none, all, explicit.",
+							Computed:    true,
+						},
+						"num_pending": schema.Int64Attribute{
+							Description: "Number of stream messages matching the consumer that are yet to be delivered",
+							Computed:    true,
+						},
+						"num_ack_pending": schema.Int64Attribute{
+							Description: "Number of delivered messages waiting for an acknowledgement",
+							Computed:    true,
+						},
+						"num_redelivered": schema.Int64Attribute{
+							Description: "Number of messages that were delivered more than once",
+							Computed:    true,
+						},
+						"num_waiting": schema.Int64Attribute{
+							Description: "Number of pull requests waiting for messages",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *consumersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data.Client
+	resp.Diagnostics.Append(data.checkServer()...)
+}
+
+func (d *consumersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// 1. Read config
+	var config consumersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. List the consumers
+	consumerInfos, err := d.client.WithDomain(config.Domain.ValueString()).ListConsumers(config.StreamName.ValueString())
+	if err != nil {
+		if errors.Is(err, nats.ErrNotFound) {
+			resp.Diagnostics.AddError("Stream not found", fmt.Sprintf("Stream %q does not exist", config.StreamName.ValueString()))
+			return
+		}
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to list consumers: %s", err))
+		return
+	}
+	// 3. Write state
+	state := config
+	state.Consumers = []consumerSummaryModel{}
+	sort.Slice(consumerInfos, func(i, j int) bool { return consumerInfos[i].Name < consumerInfos[j].Name })
+	for _, consumerInfo := range consumerInfos {
+		state.Consumers = append(state.Consumers, fromConsumerInfoSummary(consumerInfo))
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func fromConsumerInfoSummary(consumerInfo nats.ConsumerInfo) consumerSummaryModel {
+	return consumerSummaryModel{
+		Name:           types.StringValue(consumerInfo.Name),
+		Mode:           types.StringValue(consumerMode(consumerInfo)),
+		FilterSubjects: convertSlice(consumerInfo.Config.Filters(), types.StringValue),
+		AckPolicy:      types.StringValue(nats.FromAckPolicy(consumerInfo.Config.AckPolicy)),
+		NumPending:     types.Int64Value(int64(consumerInfo.NumPending)),
+		NumAckPending:  types.Int64Value(int64(consumerInfo.NumAckPending)),
+		NumRedelivered: types.Int64Value(int64(consumerInfo.NumRedelivered)),
+		NumWaiting:     types.Int64Value(int64(consumerInfo.NumWaiting)),
+	}
+}
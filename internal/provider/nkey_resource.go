@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithImportState = &nkeyResource{}
+
+func NewNKeyResource() resource.Resource {
+	return &nkeyResource{}
+}
+
+// nkeyResource needs no server connection, keys are generated locally.
+type nkeyResource struct{}
+
+type nkeyResourceModel struct {
+	Type      types.String `tfsdk:"type"`
+	PublicKey types.String `tfsdk:"public_key"`
+	Seed      types.String `tfsdk:"seed"`
+}
+
+func (r *nkeyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nkey"
+}
+
+func (r *nkeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "NKey resource, generates a key pair locally without connecting to the server. An existing seed can be imported with `terraform import nats_nkey.<name> <seed>`",
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Description: "The key type. Possible values: operator, account, user, server, curve.",
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.OneOf("operator", "account", "user", "server", "curve")},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"public_key": schema.StringAttribute{
+				Description: "The public key",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"seed": schema.StringAttribute{
+				Description: "The seed of the key pair, from which the private key is derived",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *nkeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// 1. Read plan
+	var data nkeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Generate the key pair
+	key, err := nats.CreateNKey(data.Type.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Key error", fmt.Sprintf("Failed to create nkey: %s", err))
+		return
+	}
+	// 3. Write state
+	data = fromNKey(key)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *nkeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The key pair only lives in the state, there is nothing to refresh
+	var data nkeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *nkeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every attribute requires replacement, so there is nothing to update
+	var data nkeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *nkeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The key pair only lives in the state, removing the resource from the state is enough
+}
+
+func (r *nkeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	key, err := nats.ParseNKeySeed(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import id", fmt.Sprintf("The import id must be an nkey seed: %s", err))
+		return
+	}
+	data := fromNKey(key)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func fromNKey(key nats.NKey) nkeyResourceModel {
+	return nkeyResourceModel{
+		Type:      types.StringValue(key.Type),
+		PublicKey: types.StringValue(key.PublicKey),
+		Seed:      types.StringValue(key.Seed),
+	}
+}
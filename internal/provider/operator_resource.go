@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithModifyPlan = &operatorResource{}
+
+func NewOperatorResource() resource.Resource {
+	return &operatorResource{}
+}
+
+// operatorResource needs no server connection, the JWT is issued locally.
+type operatorResource struct{}
+
+type operatorResourceModel struct {
+	Name          types.String   `tfsdk:"name"`
+	Seed          types.String   `tfsdk:"seed"`
+	SigningKeys   []types.String `tfsdk:"signing_keys"`
+	SystemAccount types.String   `tfsdk:"system_account"`
+
+	PublicKey types.String `tfsdk:"public_key"`
+	JWT       types.String `tfsdk:"jwt"`
+}
+
+func (r *operatorResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_operator"
+}
+
+func (r *operatorResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Operator resource, issues an operator JWT locally without connecting to the server. The JWT is only signed again when its claims change",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"seed": schema.StringAttribute{
+				Description: "The seed of the operator nkey, the operator JWT is self-signed with it",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"signing_keys": schema.ListAttribute{
+				Description: "Public operator nkeys that can sign account JWTs on behalf of the operator",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"system_account": schema.StringAttribute{
+				Description: "The public key of the system account",
+				Optional:    true,
+			},
+			"public_key": schema.StringAttribute{
+				Description: "The public key of the operator",
+				Computed:    true,
+			},
+			"jwt": schema.StringAttribute{
+				Description: "The encoded operator JWT",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *operatorResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// The JWT can only be issued once every claim is known
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+	var plan operatorResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	token, publicKey, err := nats.IssueOperatorJWT(toOperatorSpec(plan), plan.Seed.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid operator", fmt.Sprintf("Failed to issue operator JWT: %s", err))
+		return
+	}
+	state := types.StringNull()
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("jwt"), &state)...)
+	}
+	plan.PublicKey = types.StringValue(publicKey)
+	plan.JWT = planJWT(state, token)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *operatorResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// 1. Read plan
+	var data operatorResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Issue the JWT
+	resp.Diagnostics.Append(data.issue()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 3. Write state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *operatorResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The JWT only lives in the state, there is nothing to refresh
+	var data operatorResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *operatorResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// 1. Read plan
+	var data operatorResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Issue the JWT, unless the JWT of the state was kept when planning
+	resp.Diagnostics.Append(data.issue()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 3. Write new state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *operatorResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The JWT only lives in the state, removing the resource from the state is enough
+}
+
+func (data *operatorResourceModel) issue() diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !data.JWT.IsUnknown() {
+		return diags
+	}
+	token, publicKey, err := nats.IssueOperatorJWT(toOperatorSpec(*data), data.Seed.ValueString())
+	if err != nil {
+		diags.AddError("Invalid operator", fmt.Sprintf("Failed to issue operator JWT: %s", err))
+		return diags
+	}
+	data.PublicKey = types.StringValue(publicKey)
+	data.JWT = types.StringValue(token)
+	return diags
+}
+
+func toOperatorSpec(data operatorResourceModel) nats.OperatorSpec {
+	return nats.OperatorSpec{
+		Name:          data.Name.ValueString(),
+		SigningKeys:   convertSlice(data.SigningKeys, (types.String).ValueString),
+		SystemAccount: data.SystemAccount.ValueString(),
+	}
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &parseDurationFunction{}
+
+func NewParseDurationFunction() function.Function {
+	return &parseDurationFunction{}
+}
+
+type parseDurationFunction struct{}
+
+func (f *parseDurationFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_duration"
+}
+
+func (f *parseDurationFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Parses a duration into nanoseconds",
+		Description: "Returns the duration in nanoseconds, the unit of durations such as max_age. The duration is a Go duration, e.g. 1h30m, that also accepts days (d) and weeks (w), e.g. 1w or 1d12h",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "duration",
+				Description: "The duration",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *parseDurationFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var duration string
+	resp.Error = req.Arguments.Get(ctx, &duration)
+	if resp.Error != nil {
+		return
+	}
+	d, err := nats.ParseDuration(duration)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid duration: %q", duration))
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, d.Nanoseconds())
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"sync"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// resourceData is passed to the resources and data sources. It embeds the
+// client, so that those that only need the client can use it as a
+// nats.Client.
+type resourceData struct {
+	nats.Client
+	planned *plannedResources
+	server  *serverCheck
+}
+
+// checkServer returns the diagnostics of the provider's server check, for the
+// resources and data sources that use the nats server.
+func (d *resourceData) checkServer() diag.Diagnostics {
+	if d.server == nil {
+		return nil
+	}
+	return d.server.check()
+}
+
+// plannedResources records what the resources of the configuration plan, so
+// that a resource can check its plan against the other planned resources.
+// Terraform plans the resources a resource depends on before it, and the
+// other ones in any order.
+type plannedResources struct {
+	mu           sync.Mutex
+	publicKeys   map[string]struct{}
+	streams      map[string]map[string]plannedStream
+	reservations map[string]map[string]storageReservation
+}
+
+func newPlannedResources() *plannedResources {
+	return &plannedResources{
+		publicKeys:   map[string]struct{}{},
+		streams:      map[string]map[string]plannedStream{},
+		reservations: map[string]map[string]storageReservation{},
+	}
+}
+
+// addPublicKey records the public key of a planned nats_user or nats_account.
+func (p *plannedResources) addPublicKey(publicKey string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.publicKeys[publicKey] = struct{}{}
+}
+
+// hasPublicKey reports whether a nats_user or nats_account with the public
+// key was planned. Without records, e.g. if the provider isn't configured
+// yet, it reports true so that validations are skipped.
+func (p *plannedResources) hasPublicKey(publicKey string) bool {
+	if p == nil {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.publicKeys[publicKey]
+	return ok
+}
+
+// plannedStream holds the subjects of a planned nats_stream, nil for a
+// stream planned to be destroyed.
+type plannedStream struct {
+	subjects []string
+	known    bool
+}
+
+// addStream records the subjects of a planned nats_stream, nil subjects for
+// a stream planned to be destroyed.
+func (p *plannedResources) addStream(domain, name string, subjects []string) {
+	p.setStream(domain, name, plannedStream{subjects: subjects, known: true})
+}
+
+// addUnknownStream records a planned nats_stream whose subjects are only
+// known after apply.
+func (p *plannedResources) addUnknownStream(domain, name string) {
+	p.setStream(domain, name, plannedStream{})
+}
+
+func (p *plannedResources) setStream(domain, name string, stream plannedStream) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.streams[domain] == nil {
+		p.streams[domain] = map[string]plannedStream{}
+	}
+	p.streams[domain][name] = stream
+}
+
+// plannedStreams returns the nats_stream planned in the domain, by stream
+// name.
+func (p *plannedResources) plannedStreams(domain string) map[string]plannedStream {
+	streams := map[string]plannedStream{}
+	if p == nil {
+		return streams
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for name, stream := range p.streams[domain] {
+		streams[name] = stream
+	}
+	return streams
+}
+
+// plannedStream returns the planned subjects of the named nats_stream of the
+// domain, whether they are known, and whether the stream was planned.
+func (p *plannedResources) plannedStream(domain, name string) ([]string, bool, bool) {
+	if p == nil {
+		return nil, false, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stream, ok := p.streams[domain][name]
+	return stream.subjects, stream.known, ok
+}
+
+// addReservation records the storage a planned nats_stream adds to its
+// account, as a reservation of the additional bytes.
+func (p *plannedResources) addReservation(domain, name string, reservation storageReservation) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.reservations[domain] == nil {
+		p.reservations[domain] = map[string]storageReservation{}
+	}
+	p.reservations[domain][name] = reservation
+}
+
+// otherReservations returns the storage the nats_stream planned in the
+// domain add to the account, except for the named stream.
+func (p *plannedResources) otherReservations(domain, name string) []storageReservation {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var reservations []storageReservation
+	for otherName, reservation := range p.reservations[domain] {
+		if otherName != name {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations
+}
@@ -2,34 +2,48 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"sync"
 	"terraform-provider-nats/internal/nats"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var _ provider.Provider = &NatsProvider{}
+var _ provider.ProviderWithFunctions = &NatsProvider{}
 
 // NatsProvider is the provider implementation of nats.
 type NatsProvider struct {
 	version string
+	planned *plannedResources
 }
 
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &NatsProvider{
 			version: version,
+			planned: newPlannedResources(),
 		}
 	}
 }
 
 // natsProviderModel maps provider schema to Go type.
 type natsProviderModel struct {
-	URL types.String `tfsdk:"url"`
+	URL                types.String `tfsdk:"url"`
+	Context            types.String `tfsdk:"context"`
+	JetStreamDomain    types.String `tfsdk:"jetstream_domain"`
+	JetStreamAPIPrefix types.String `tfsdk:"jetstream_api_prefix"`
+	SystemAccountCreds types.String `tfsdk:"system_account_creds"`
 }
 
 func (p *NatsProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -44,6 +58,25 @@ func (p *NatsProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 				Description: "nats url (default: 'nats://localhost:4222')",
 				Optional:    true,
 			},
+			"context": schema.StringAttribute{
+				Description: "Name of a nats CLI context to load the url, credentials, TLS, JetStream domain and inbox prefix from. Can also be set with the NATS_CONTEXT environment variable. Attributes set on the provider take precedence over the context",
+				Optional:    true,
+			},
+			"jetstream_domain": schema.StringAttribute{
+				Description: "The JetStream domain to manage, e.g. the domain of a leafnode-attached JetStream. Can be overridden per resource",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("jetstream_api_prefix")),
+				},
+			},
+			"jetstream_api_prefix": schema.StringAttribute{
+				Description: "The subject prefix of the JetStream API, for accounts importing JetStream from another account",
+				Optional:    true,
+			},
+			"system_account_creds": schema.StringAttribute{
+				Description: "Path to the creds file of a system account user, used by nats_account_jwt_push to manage the account JWTs of the resolver",
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -55,38 +88,115 @@ func (p *NatsProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		return
 	}
 
-	var url string
+	var clientConfig nats.Config
+	contextName := config.Context.ValueString()
+	if contextName == "" {
+		contextName = os.Getenv("NATS_CONTEXT")
+	}
+	if contextName != "" {
+		var err error
+		clientConfig, err = nats.LoadContext(contextName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("context"), "Invalid nats context", err.Error())
+			return
+		}
+	}
+
 	switch {
 	case !config.URL.IsNull():
-		url = config.URL.ValueString()
+		clientConfig.URL = config.URL.ValueString()
+	case clientConfig.URL != "":
 	case os.Getenv("NATS_URL") != "":
-		url = os.Getenv("NATS_URL")
+		clientConfig.URL = os.Getenv("NATS_URL")
 	default:
-		url = "nats://localhost:4222"
+		clientConfig.URL = "nats://localhost:4222"
+	}
+	if !config.JetStreamDomain.IsNull() {
+		clientConfig.JetStreamDomain = config.JetStreamDomain.ValueString()
+		clientConfig.JetStreamAPIPrefix = ""
+	}
+	if !config.JetStreamAPIPrefix.IsNull() {
+		clientConfig.JetStreamAPIPrefix = config.JetStreamAPIPrefix.ValueString()
+		clientConfig.JetStreamDomain = ""
 	}
+	clientConfig.SystemCredsFile = config.SystemAccountCreds.ValueString()
 
-	client := nats.NewClient(url)
-	// resp.Diagnostics.AddError(
-	// 	"Unable to Create HashiCups API Client",
-	// 	"An unexpected error occurred when creating the HashiCups API client. "+
-	// 		"If the error is not clear, please contact the provider developers.\n\n"+
-	// 		"HashiCups Client Error: "+err.Error(),
-	// )
-	// And return
-
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	client := nats.NewClient(clientConfig)
+	data := &resourceData{Client: client, planned: p.planned}
+	// The provider config may depend on values known only after apply, in
+	// which case the check is left to the run that has them.
+	if req.Config.Raw.IsFullyKnown() {
+		data.server = &serverCheck{client: client, config: clientConfig}
+	}
+	resp.DataSourceData = data
+	resp.ResourceData = data
 }
 
 func (p *NatsProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewStreamResource,
 		NewConsumerResource,
+		NewStreamMessageResource,
+		NewStreamPurgeResource,
+		NewStreamSnapshotResource,
+		NewNKeyResource,
+		NewOperatorResource,
+		NewAccountResource,
+		NewUserResource,
+		NewAccountJWTPushResource,
 	}
 }
 
 func (p *NatsProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewStreamDataSource,
+		NewStreamsDataSource,
+		NewConsumersDataSource,
+		NewAccountInfoDataSource,
+		NewStreamMessageDataSource,
+		NewServerConfigDataSource,
+	}
+}
+
+func (p *NatsProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewSubjectMatchesFunction,
+		NewSubjectsOverlapFunction,
+		NewTokenizeSubjectFunction,
+		NewValidSubjectFunction,
+		NewParseDurationFunction,
+	}
+}
+
+// serverCheck checks the nats server is reachable and JetStream is available.
+// It runs on the first use by a resource or data source that needs the
+// server, so that configurations of offline resources such as nats_nkey need
+// no server, and its diagnostics are returned to all of them.
+type serverCheck struct {
+	once   sync.Once
+	client nats.Client
+	config nats.Config
+	diags  diag.Diagnostics
+}
+
+func (c *serverCheck) check() diag.Diagnostics {
+	c.once.Do(func() {
+		if _, err := c.client.GetAccountInfo(); err != nil {
+			c.diags.AddError("Unable to use the nats server", connectionErrorDetail(err, c.config))
+		}
+	})
+	return c.diags
+}
+
+func connectionErrorDetail(err error, config nats.Config) string {
+	switch {
+	case errors.Is(err, nats.ErrUnauthorized):
+		return fmt.Sprintf("The nats server rejected the configured credentials. Check the provider's context and credentials.\n\nError: %s", err)
+	case errors.Is(err, nats.ErrJetStreamNotEnabled) && config.JetStreamDomain != "":
+		return fmt.Sprintf("No JetStream responded in domain %q. Check that jetstream_domain is correct and that the domain is reachable from this account.\n\nError: %s", config.JetStreamDomain, err)
+	case errors.Is(err, nats.ErrJetStreamNotEnabled):
+		return fmt.Sprintf("JetStream is not enabled on the nats server or for this account.\n\nError: %s", err)
+	default:
+		return fmt.Sprintf("Failed to reach the nats server. Check the provider's url and connection settings.\n\nError: %s", err)
 	}
 }
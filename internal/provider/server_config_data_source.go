@@ -0,0 +1,484 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &serverConfigDataSource{}
+
+// NewServerConfigDataSource creates a new server config datasource.
+func NewServerConfigDataSource() datasource.DataSource {
+	return &serverConfigDataSource{}
+}
+
+// serverConfigDataSource needs no server connection, the file is rendered
+// and validated locally.
+type serverConfigDataSource struct{}
+
+type serverConfigDataSourceModel struct {
+	ServerName    types.String                  `tfsdk:"server_name"`
+	Listen        types.String                  `tfsdk:"listen"`
+	TLS           *serverTLSModel               `tfsdk:"tls"`
+	JetStream     *serverJetStreamModel         `tfsdk:"jetstream"`
+	Cluster       *serverClusterModel           `tfsdk:"cluster"`
+	Gateway       *serverGatewayModel           `tfsdk:"gateway"`
+	LeafNodes     *serverLeafNodesModel         `tfsdk:"leafnodes"`
+	Accounts      map[string]serverAccountModel `tfsdk:"accounts"`
+	SystemAccount types.String                  `tfsdk:"system_account"`
+	MQTT          *serverMQTTModel              `tfsdk:"mqtt"`
+	Websocket     *serverWebsocketModel         `tfsdk:"websocket"`
+
+	Config types.String `tfsdk:"config"`
+}
+
+type serverTLSModel struct {
+	CertFile types.String `tfsdk:"cert_file"`
+	KeyFile  types.String `tfsdk:"key_file"`
+	CAFile   types.String `tfsdk:"ca_file"`
+	Verify   types.Bool   `tfsdk:"verify"`
+}
+
+type serverJetStreamModel struct {
+	StoreDir       types.String `tfsdk:"store_dir"`
+	MaxMemoryStore types.Int64  `tfsdk:"max_memory_store"`
+	MaxFileStore   types.Int64  `tfsdk:"max_file_store"`
+	Domain         types.String `tfsdk:"domain"`
+}
+
+type serverClusterModel struct {
+	Name   types.String    `tfsdk:"name"`
+	Listen types.String    `tfsdk:"listen"`
+	Routes []types.String  `tfsdk:"routes"`
+	TLS    *serverTLSModel `tfsdk:"tls"`
+}
+
+type serverGatewayModel struct {
+	Name     types.String         `tfsdk:"name"`
+	Listen   types.String         `tfsdk:"listen"`
+	Gateways []remoteGatewayModel `tfsdk:"gateways"`
+	TLS      *serverTLSModel      `tfsdk:"tls"`
+}
+
+type remoteGatewayModel struct {
+	Name types.String   `tfsdk:"name"`
+	URLs []types.String `tfsdk:"urls"`
+}
+
+type serverLeafNodesModel struct {
+	Listen  types.String          `tfsdk:"listen"`
+	Remotes []leafNodeRemoteModel `tfsdk:"remotes"`
+	TLS     *serverTLSModel       `tfsdk:"tls"`
+}
+
+type leafNodeRemoteModel struct {
+	URLs        []types.String `tfsdk:"urls"`
+	Account     types.String   `tfsdk:"account"`
+	Credentials types.String   `tfsdk:"credentials"`
+}
+
+type serverAccountModel struct {
+	JetStream types.Bool        `tfsdk:"jetstream"`
+	Users     []serverUserModel `tfsdk:"users"`
+}
+
+type serverUserModel struct {
+	User        types.String            `tfsdk:"user"`
+	Password    types.String            `tfsdk:"password"`
+	NKey        types.String            `tfsdk:"nkey"`
+	Permissions *serverPermissionsModel `tfsdk:"permissions"`
+}
+
+type serverPermissionsModel struct {
+	PubAllow []types.String `tfsdk:"pub_allow"`
+	PubDeny  []types.String `tfsdk:"pub_deny"`
+	SubAllow []types.String `tfsdk:"sub_allow"`
+	SubDeny  []types.String `tfsdk:"sub_deny"`
+}
+
+type serverMQTTModel struct {
+	Listen types.String    `tfsdk:"listen"`
+	TLS    *serverTLSModel `tfsdk:"tls"`
+}
+
+type serverWebsocketModel struct {
+	Listen types.String    `tfsdk:"listen"`
+	NoTLS  types.Bool      `tfsdk:"no_tls"`
+	TLS    *serverTLSModel `tfsdk:"tls"`
+}
+
+func (d *serverConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_config"
+}
+
+func (d *serverConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	stringList := func(description string, required bool) schema.ListAttribute {
+		return schema.ListAttribute{
+			Description: description,
+			ElementType: types.StringType,
+			Required:    required,
+			Optional:    !required,
+			Validators:  []validator.List{listvalidator.SizeAtLeast(1)},
+		}
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Server config data source, renders a nats-server configuration file without connecting to the server. The file is validated with the parser of the server, TLS files excepted as they live on the servers",
+		Attributes: map[string]schema.Attribute{
+			"server_name": schema.StringAttribute{
+				Optional: true,
+			},
+			"listen": schema.StringAttribute{
+				Description: "The host:port to listen on for clients. Defaults to 0.0.0.0:4222",
+				Optional:    true,
+			},
+			"tls": serverTLSAttribute("The TLS of the client connections"),
+			"jetstream": schema.SingleNestedAttribute{
+				Description: "Enables JetStream",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"store_dir": schema.StringAttribute{
+						Description: "The directory of the file storage",
+						Required:    true,
+					},
+					"max_memory_store": schema.Int64Attribute{
+						Description: "The maximum bytes of memory storage. Defaults to 75% of the memory",
+						Optional:    true,
+						Validators:  []validator.Int64{int64validator.AtLeast(1)},
+					},
+					"max_file_store": schema.Int64Attribute{
+						Description: "The maximum bytes of file storage. Defaults to 1TB or 75% of the disk",
+						Optional:    true,
+						Validators:  []validator.Int64{int64validator.AtLeast(1)},
+					},
+					"domain": schema.StringAttribute{
+						Description: "The JetStream domain of the server",
+						Optional:    true,
+					},
+				},
+			},
+			"cluster": schema.SingleNestedAttribute{
+				Description: "Clusters the server with its routes",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Optional: true,
+					},
+					"listen": schema.StringAttribute{
+						Description: "The host:port to listen on for routes",
+						Required:    true,
+					},
+					"routes": stringList("The URLs of the other servers of the cluster", false),
+					"tls":    serverTLSAttribute("The TLS of the routes"),
+				},
+			},
+			"gateway": schema.SingleNestedAttribute{
+				Description: "Connects the cluster to other clusters, making a super cluster",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Description: "The name of the cluster of the server",
+						Required:    true,
+					},
+					"listen": schema.StringAttribute{
+						Description: "The host:port to listen on for gateways",
+						Required:    true,
+					},
+					"gateways": schema.ListNestedAttribute{
+						Description: "The other clusters",
+						Optional:    true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"name": schema.StringAttribute{
+									Required: true,
+								},
+								"urls": stringList("The gateway URLs of the servers of the cluster", true),
+							},
+						},
+					},
+					"tls": serverTLSAttribute("The TLS of the gateways"),
+				},
+			},
+			"leafnodes": schema.SingleNestedAttribute{
+				Description: "Accepts leaf node connections and connects to remote servers as a leaf node",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"listen": schema.StringAttribute{
+						Description: "The host:port to listen on for leaf nodes. Not set, the server only connects to its remotes",
+						Optional:    true,
+					},
+					"remotes": schema.ListNestedAttribute{
+						Description: "The servers to connect to as a leaf node",
+						Optional:    true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"urls": stringList("The leaf node URLs of the remote servers", true),
+								"account": schema.StringAttribute{
+									Description: "The local account to bind the connection to",
+									Optional:    true,
+								},
+								"credentials": schema.StringAttribute{
+									Description: "The path of the creds file to authenticate with",
+									Optional:    true,
+								},
+							},
+						},
+					},
+					"tls": serverTLSAttribute("The TLS of the leaf nodes"),
+				},
+			},
+			"accounts": schema.MapNestedAttribute{
+				Description: "The accounts, by name",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"jetstream": schema.BoolAttribute{
+							Description: "Enables JetStream for the account",
+							Optional:    true,
+						},
+						"users": schema.ListNestedAttribute{
+							Optional: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"user": schema.StringAttribute{
+										Description: "The name of the user. Exactly one of user and nkey must be set",
+										Optional:    true,
+										Validators: []validator.String{
+											stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("nkey")),
+										},
+									},
+									"password": schema.StringAttribute{
+										Description: "The password of the user, plain or bcrypted",
+										Optional:    true,
+										Sensitive:   true,
+										Validators: []validator.String{
+											stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("user")),
+										},
+									},
+									"nkey": schema.StringAttribute{
+										Description: "The public key of the user, to authenticate with its seed instead of a password",
+										Optional:    true,
+										Validators:  []validator.String{publicKeyValidator{keyType: "user"}},
+									},
+									"permissions": schema.SingleNestedAttribute{
+										Optional: true,
+										Attributes: map[string]schema.Attribute{
+											"pub_allow": stringList("Subjects the user can publish to", false),
+											"pub_deny":  stringList("Subjects the user can't publish to", false),
+											"sub_allow": stringList("Subjects the user can subscribe to", false),
+											"sub_deny":  stringList("Subjects the user can't subscribe to", false),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"system_account": schema.StringAttribute{
+				Description: "The name of the system account",
+				Optional:    true,
+			},
+			"mqtt": schema.SingleNestedAttribute{
+				Description: "Accepts MQTT clients, it requires JetStream",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"listen": schema.StringAttribute{
+						Description: "The host:port to listen on for MQTT clients",
+						Required:    true,
+					},
+					"tls": serverTLSAttribute("The TLS of the MQTT connections"),
+				},
+			},
+			"websocket": schema.SingleNestedAttribute{
+				Description: "Accepts websocket clients",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"listen": schema.StringAttribute{
+						Description: "The host:port to listen on for websocket clients",
+						Required:    true,
+					},
+					"no_tls": schema.BoolAttribute{
+						Description: "Accepts websocket clients without TLS. Exactly one of no_tls and tls must be set",
+						Optional:    true,
+						Validators: []validator.Bool{
+							boolvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("tls")),
+						},
+					},
+					"tls": serverTLSAttribute("The TLS of the websocket connections"),
+				},
+			},
+			"config": schema.StringAttribute{
+				Description: "The content of the configuration file, sensitive as it holds the passwords of the users",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func serverTLSAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: description,
+		Optional:    true,
+		Attributes: map[string]schema.Attribute{
+			"cert_file": schema.StringAttribute{
+				Description: "The path of the certificate on the server",
+				Required:    true,
+			},
+			"key_file": schema.StringAttribute{
+				Description: "The path of the certificate key on the server",
+				Required:    true,
+			},
+			"ca_file": schema.StringAttribute{
+				Description: "The path of the CA certificate on the server, to verify the peer certificates with",
+				Optional:    true,
+			},
+			"verify": schema.BoolAttribute{
+				Description: "Requires the peers to present a certificate",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (d *serverConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// 1. Read config
+	var config serverConfigDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Validate the rendered file
+	spec := toServerConfig(config)
+	warnings, err := nats.ValidateServerConfig(spec)
+	for _, warning := range warnings {
+		resp.Diagnostics.AddWarning("Server config warning", warning)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid server config", fmt.Sprintf("The server rejects the rendered configuration file:\n%s", err))
+		return
+	}
+	// 3. Write state
+	config.Config = types.StringValue(nats.RenderServerConfig(spec))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+func toServerConfig(data serverConfigDataSourceModel) nats.ServerConfig {
+	config := nats.ServerConfig{
+		ServerName:    data.ServerName.ValueString(),
+		Listen:        data.Listen.ValueString(),
+		TLS:           toServerTLS(data.TLS),
+		SystemAccount: data.SystemAccount.ValueString(),
+	}
+	if js := data.JetStream; js != nil {
+		config.JetStream = &nats.ServerJetStream{
+			StoreDir:       js.StoreDir.ValueString(),
+			MaxMemoryStore: js.MaxMemoryStore.ValueInt64(),
+			MaxFileStore:   js.MaxFileStore.ValueInt64(),
+			Domain:         js.Domain.ValueString(),
+		}
+	}
+	if cluster := data.Cluster; cluster != nil {
+		config.Cluster = &nats.ServerCluster{
+			Name:   cluster.Name.ValueString(),
+			Listen: cluster.Listen.ValueString(),
+			Routes: convertSlice(cluster.Routes, (types.String).ValueString),
+			TLS:    toServerTLS(cluster.TLS),
+		}
+	}
+	if gateway := data.Gateway; gateway != nil {
+		config.Gateway = &nats.ServerGateway{
+			Name:   gateway.Name.ValueString(),
+			Listen: gateway.Listen.ValueString(),
+			Gateways: convertSlice(gateway.Gateways, func(remote remoteGatewayModel) nats.RemoteGateway {
+				return nats.RemoteGateway{
+					Name: remote.Name.ValueString(),
+					URLs: convertSlice(remote.URLs, (types.String).ValueString),
+				}
+			}),
+			TLS: toServerTLS(gateway.TLS),
+		}
+	}
+	if leafNodes := data.LeafNodes; leafNodes != nil {
+		config.LeafNodes = &nats.ServerLeafNodes{
+			Listen: leafNodes.Listen.ValueString(),
+			Remotes: convertSlice(leafNodes.Remotes, func(remote leafNodeRemoteModel) nats.LeafNodeRemote {
+				return nats.LeafNodeRemote{
+					URLs:        convertSlice(remote.URLs, (types.String).ValueString),
+					Account:     remote.Account.ValueString(),
+					Credentials: remote.Credentials.ValueString(),
+				}
+			}),
+			TLS: toServerTLS(leafNodes.TLS),
+		}
+	}
+	// Sorted by name, for the file not to change between reads
+	names := make([]string, 0, len(data.Accounts))
+	for name := range data.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		account := data.Accounts[name]
+		config.Accounts = append(config.Accounts, nats.ServerAccount{
+			Name:      name,
+			JetStream: account.JetStream.ValueBool(),
+			Users:     convertSlice(account.Users, toServerUser),
+		})
+	}
+	if mqtt := data.MQTT; mqtt != nil {
+		config.MQTT = &nats.ServerMQTT{
+			Listen: mqtt.Listen.ValueString(),
+			TLS:    toServerTLS(mqtt.TLS),
+		}
+	}
+	if websocket := data.Websocket; websocket != nil {
+		config.Websocket = &nats.ServerWebsocket{
+			Listen: websocket.Listen.ValueString(),
+			NoTLS:  websocket.NoTLS.ValueBool(),
+			TLS:    toServerTLS(websocket.TLS),
+		}
+	}
+	return config
+}
+
+func toServerTLS(data *serverTLSModel) *nats.ServerTLS {
+	if data == nil {
+		return nil
+	}
+	return &nats.ServerTLS{
+		CertFile: data.CertFile.ValueString(),
+		KeyFile:  data.KeyFile.ValueString(),
+		CAFile:   data.CAFile.ValueString(),
+		Verify:   data.Verify.ValueBool(),
+	}
+}
+
+func toServerUser(data serverUserModel) nats.ServerUser {
+	user := nats.ServerUser{
+		User:     data.User.ValueString(),
+		Password: data.Password.ValueString(),
+		NKey:     data.NKey.ValueString(),
+	}
+	if p := data.Permissions; p != nil {
+		user.Permissions = &nats.ServerPermissions{
+			PubAllow: convertSlice(p.PubAllow, (types.String).ValueString),
+			PubDeny:  convertSlice(p.PubDeny, (types.String).ValueString),
+			SubAllow: convertSlice(p.SubAllow, (types.String).ValueString),
+			SubDeny:  convertSlice(p.SubDeny, (types.String).ValueString),
+		}
+	}
+	return user
+}
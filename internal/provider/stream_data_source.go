@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -21,8 +22,6 @@ type streamDataSource struct {
 	client nats.Client
 }
 
-type streamDataSourceModel streamResourceModel
-
 func (d *streamDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_stream"
 }
@@ -34,6 +33,10 @@ func (d *streamDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 			"name": schema.StringAttribute{
 				Required: true,
 			},
+			"domain": schema.StringAttribute{
+				Description: "The JetStream domain of the stream. Defaults to the provider's jetstream_domain",
+				Optional:    true,
+			},
 			"subjects": schema.ListAttribute{
 				ElementType: types.StringType,
 				Computed:    true,
@@ -86,6 +89,15 @@ func (d *streamDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 				Description: "If true, and the stream has more than one replica, each replica will respond to direct get requests for individual messages, not only the leader",
 				Computed:    true,
 			},
+			"subjects_filter": schema.StringAttribute{
+				Description: "If set, state.subjects reports the message count of each subject matching this filter, wildcards allowed",
+				Optional:    true,
+			},
+			"state": schema.SingleNestedAttribute{
+				Description: "The runtime state of the stream",
+				Computed:    true,
+				Attributes:  dataSourceAttributes(streamStateAttributes()),
+			},
 		},
 	}
 }
@@ -95,34 +107,38 @@ func (d *streamDataSource) Configure(ctx context.Context, req datasource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(nats.Client)
+	data, ok := req.ProviderData.(*resourceData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected nats.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = data.Client
+	resp.Diagnostics.Append(data.checkServer()...)
 }
 
 func (d *streamDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	// 1. Read config
-	var config streamDataSourceModel
-	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	var name, domain, subjectsFilter types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("domain"), &domain)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("subjects_filter"), &subjectsFilter)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	// 2. Read the resource
-	streamName := config.Name.ValueString()
-	streamInfo, err := d.client.GetStream(streamName)
+	streamInfo, err := d.client.WithDomain(domain.ValueString()).GetStream(name.ValueString(), subjectsFilter.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to get stream: %s", err))
 		return
 	}
 
-	// 4. Write state
-	state := streamDataSourceModel(fromStreamInfo(streamInfo))
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	// 3. Write state, from the model of the stream resource
+	state := fromStreamInfo(streamInfo)
+	state.Domain = domain
+	state.SubjectsFilter = subjectsFilter
+	resp.Diagnostics.Append(setFromResourceModel(ctx, NewStreamResource(), &state, &resp.State)...)
 }
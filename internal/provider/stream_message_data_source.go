@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &streamMessageDataSource{}
+
+// NewStreamMessageDataSource creates a new stream message datasource.
+func NewStreamMessageDataSource() datasource.DataSource {
+	return &streamMessageDataSource{}
+}
+
+type streamMessageDataSource struct {
+	client nats.Client
+}
+
+type streamMessageDataSourceModel struct {
+	StreamName    types.String `tfsdk:"stream_name"`
+	Domain        types.String `tfsdk:"domain"`
+	Sequence      types.Int64  `tfsdk:"sequence"`
+	LastBySubject types.String `tfsdk:"last_by_subject"`
+
+	Subject    types.String              `tfsdk:"subject"`
+	Data       types.String              `tfsdk:"data"`
+	DataBase64 types.String              `tfsdk:"data_base64"`
+	Headers    map[string][]types.String `tfsdk:"headers"`
+	Timestamp  types.String              `tfsdk:"timestamp"`
+}
+
+func (d *streamMessageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stream_message"
+}
+
+func (d *streamMessageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Stream message data source, reads a message stored in a stream by sequence or as the last message on a subject",
+		Attributes: map[string]schema.Attribute{
+			"stream_name": schema.StringAttribute{
+				Required: true,
+			},
+			"domain": schema.StringAttribute{
+				Description: "The JetStream domain of the stream. Defaults to the provider's jetstream_domain",
+				Optional:    true,
+			},
+			"sequence": schema.Int64Attribute{
+				Description: "The sequence of the message to read. Exactly one of sequence and last_by_subject must be set",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.ExactlyOneOf(path.MatchRoot("last_by_subject")),
+				},
+			},
+			"last_by_subject": schema.StringAttribute{
+				Description: "Read the last message stored on this subject",
+				Optional:    true,
+			},
+			"subject": schema.StringAttribute{
+				Description: "The subject the message was published on",
+				Computed:    true,
+			},
+			"data": schema.StringAttribute{
+				Description: "The message payload as a string, null if the payload is not valid UTF-8",
+				Computed:    true,
+			},
+			"data_base64": schema.StringAttribute{
+				Description: "The message payload, base64 encoded",
+				Computed:    true,
+			},
+			"headers": schema.MapAttribute{
+				Description: "The message headers",
+				ElementType: types.ListType{ElemType: types.StringType},
+				Computed:    true,
+			},
+			"timestamp": schema.StringAttribute{
+				Description: "The time the message was stored, in RFC3339 format",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *streamMessageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data.Client
+	resp.Diagnostics.Append(data.checkServer()...)
+}
+
+func (d *streamMessageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// 1. Read config
+	var config streamMessageDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Read the message
+	client := d.client.WithDomain(config.Domain.ValueString())
+	streamName := config.StreamName.ValueString()
+	var msg nats.StreamMessage
+	var err error
+	if config.LastBySubject.IsNull() {
+		msg, err = client.GetMessage(streamName, uint64(config.Sequence.ValueInt64()))
+	} else {
+		msg, err = client.GetLastMessage(streamName, config.LastBySubject.ValueString())
+	}
+	if err != nil {
+		if errors.Is(err, nats.ErrNotFound) {
+			resp.Diagnostics.AddError("Message not found", fmt.Sprintf("Couldn't find the message or the stream %q", streamName))
+			return
+		}
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to get message: %s", err))
+		return
+	}
+	// 3. Write state
+	state := config
+	state.Sequence = types.Int64Value(int64(msg.Sequence))
+	state.Subject = types.StringValue(msg.Subject)
+	state.Data = types.StringNull()
+	if utf8.Valid(msg.Data) {
+		state.Data = types.StringValue(string(msg.Data))
+	}
+	state.DataBase64 = types.StringValue(base64.StdEncoding.EncodeToString(msg.Data))
+	state.Headers = fromHeaders(msg.Header)
+	state.Timestamp = timestampValue(msg.Time)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func fromHeaders(header map[string][]string) map[string][]types.String {
+	if len(header) == 0 {
+		return nil
+	}
+	headers := make(map[string][]types.String, len(header))
+	for key, values := range header {
+		headers[key] = convertSlice(values, types.StringValue)
+	}
+	return headers
+}
@@ -0,0 +1,272 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &streamMessageResource{}
+
+func NewStreamMessageResource() resource.Resource {
+	return &streamMessageResource{}
+}
+
+type streamMessageResource struct {
+	client nats.Client
+}
+
+type streamMessageResourceModel struct {
+	Subject              types.String            `tfsdk:"subject"`
+	Data                 types.String            `tfsdk:"data"`
+	Headers              map[string]types.String `tfsdk:"headers"`
+	Domain               types.String            `tfsdk:"domain"`
+	Key                  types.String            `tfsdk:"key"`
+	MsgID                types.String            `tfsdk:"msg_id"`
+	ExpectedStream       types.String            `tfsdk:"expected_stream"`
+	ExpectedLastSequence types.Int64             `tfsdk:"expected_last_sequence"`
+	DeleteOnDestroy      types.Bool              `tfsdk:"delete_on_destroy"`
+
+	StreamName types.String `tfsdk:"stream_name"`
+	Sequence   types.Int64  `tfsdk:"sequence"`
+	Duplicate  types.Bool   `tfsdk:"duplicate"`
+}
+
+func (r *streamMessageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stream_message"
+}
+
+func (r *streamMessageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Stream message resource, publishes a message to a stream once. Changing the message publishes a new one",
+		Attributes: map[string]schema.Attribute{
+			"subject": schema.StringAttribute{
+				Description: "The subject to publish the message on",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"data": schema.StringAttribute{
+				Description: "The message payload",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"headers": schema.MapAttribute{
+				Description: "The message headers",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Description: "The JetStream domain to publish to. Defaults to the provider's jetstream_domain",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key": schema.StringAttribute{
+				Description: "Identifies the message among the messages of the configuration published to the stream, for the default msg_id. Defaults to the subject",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"msg_id": schema.StringAttribute{
+				Description: "The Nats-Msg-Id used by the stream to deduplicate the message. Defaults to an id derived from the stream and the key, so that a message published again within the duplicate window of the stream, e.g. by a retried apply or a changed message with the same key, is a duplicate",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"expected_stream": schema.StringAttribute{
+				Description: "Only store the message if the subject is bound to this stream",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"expected_last_sequence": schema.Int64Attribute{
+				Description: "Only store the message if the last sequence of the stream is this one",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(0)},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"delete_on_destroy": schema.BoolAttribute{
+				Description: "Whether to delete the message from the stream when the resource is destroyed",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"stream_name": schema.StringAttribute{
+				Description: "The stream that stored the message",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sequence": schema.Int64Attribute{
+				Description: "The sequence of the message in the stream, null if the message was a duplicate",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"duplicate": schema.BoolAttribute{
+				Description: "Whether the stream already had a message with the same msg_id, in which case the message wasn't stored and isn't deleted on destroy",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *streamMessageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.Client
+	resp.Diagnostics.Append(data.checkServer()...)
+}
+
+func (r *streamMessageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// 1. Read plan
+	var data streamMessageResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	client := r.client.WithDomain(data.Domain.ValueString())
+	// 2. Derive the msg_id from the stream of the subject
+	if data.MsgID.IsUnknown() || data.MsgID.ValueString() == "" {
+		stream := data.ExpectedStream.ValueString()
+		if stream == "" {
+			streams, err := client.ListStreams(data.Subject.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to find the stream of the subject: %s", err))
+				return
+			}
+			if len(streams) == 0 {
+				resp.Diagnostics.AddAttributeError(path.Root("subject"), "Invalid subject", fmt.Sprintf("No stream stores the subject %q", data.Subject.ValueString()))
+				return
+			}
+			stream = streams[0].Config.Name
+		}
+		key := data.Subject.ValueString()
+		if !data.Key.IsNull() {
+			key = data.Key.ValueString()
+		}
+		data.MsgID = types.StringValue(deriveMsgID(stream, key))
+	}
+	// 3. Publish the message
+	opts := nats.PublishOptions{
+		MsgID:          data.MsgID.ValueString(),
+		ExpectedStream: data.ExpectedStream.ValueString(),
+	}
+	if !data.ExpectedLastSequence.IsNull() {
+		expectedLastSequence := uint64(data.ExpectedLastSequence.ValueInt64())
+		opts.ExpectedLastSequence = &expectedLastSequence
+	}
+	headers := make(map[string][]string, len(data.Headers))
+	for key, value := range data.Headers {
+		headers[key] = []string{value.ValueString()}
+	}
+	ack, err := client.PublishMessage(data.Subject.ValueString(), []byte(data.Data.ValueString()), headers, opts)
+	if err != nil {
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to publish message: %s", err))
+		return
+	}
+	// 4. Write state, the sequence of a duplicate is the one of the message
+	// already stored, which is not this resource's to delete
+	data.StreamName = types.StringValue(ack.Stream)
+	data.Sequence = types.Int64Null()
+	if !ack.Duplicate {
+		data.Sequence = types.Int64Value(int64(ack.Sequence))
+	}
+	data.Duplicate = types.BoolValue(ack.Duplicate)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *streamMessageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The message may be removed by the stream limits at any time, and that
+	// must not publish it again, so the state is kept as is.
+	var data streamMessageResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *streamMessageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only delete_on_destroy can be updated in place
+	var plan streamMessageResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state streamMessageResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.DeleteOnDestroy = plan.DeleteOnDestroy
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *streamMessageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// 1. Read current state
+	var state streamMessageResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !state.DeleteOnDestroy.ValueBool() || state.Sequence.IsNull() {
+		return
+	}
+	// 2. Delete the message
+	err := r.client.WithDomain(state.Domain.ValueString()).DeleteMessage(state.StreamName.ValueString(), uint64(state.Sequence.ValueInt64()))
+	if err != nil && !errors.Is(err, nats.ErrNotFound) {
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to delete message: %s", err))
+		return
+	}
+}
+
+// deriveMsgID identifies the message by its stream and key, so that retried
+// applies are deduplicated by the stream within its duplicate window.
+func deriveMsgID(stream, key string) string {
+	return fmt.Sprintf("tf-%s-%s", stream, key)
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &streamPurgeResource{}
+
+func NewStreamPurgeResource() resource.Resource {
+	return &streamPurgeResource{}
+}
+
+type streamPurgeResource struct {
+	client nats.Client
+}
+
+type streamPurgeResourceModel struct {
+	StreamName types.String            `tfsdk:"stream_name"`
+	Domain     types.String            `tfsdk:"domain"`
+	Subject    types.String            `tfsdk:"subject"`
+	Sequence   types.Int64             `tfsdk:"sequence"`
+	Keep       types.Int64             `tfsdk:"keep"`
+	Triggers   map[string]types.String `tfsdk:"triggers"`
+
+	Purged types.Int64 `tfsdk:"purged"`
+}
+
+func (r *streamPurgeResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stream_purge"
+}
+
+func (r *streamPurgeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Stream purge resource, purges a stream when created. Changing any attribute, including triggers, purges the stream again. Destroying it does nothing",
+		Attributes: map[string]schema.Attribute{
+			"stream_name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Description: "The JetStream domain of the stream. Defaults to the provider's jetstream_domain",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subject": schema.StringAttribute{
+				Description: "Only purge messages on subjects matching this subject, wildcards allowed",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"sequence": schema.Int64Attribute{
+				Description: "Purge messages up to, but not including, this sequence",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.ConflictsWith(path.MatchRoot("keep")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"keep": schema.Int64Attribute{
+				Description: "Keep this many of the most recent messages",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Description: "Arbitrary values that, when changed, purge the stream again",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"purged": schema.Int64Attribute{
+				Description: "The number of purged messages",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *streamPurgeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.Client
+	resp.Diagnostics.Append(data.checkServer()...)
+}
+
+func (r *streamPurgeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// 1. Read plan
+	var data streamPurgeResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Purge the stream
+	purged, err := r.client.WithDomain(data.Domain.ValueString()).PurgeStream(data.StreamName.ValueString(), nats.PurgeOptions{
+		Subject:  data.Subject.ValueString(),
+		Sequence: uint64(data.Sequence.ValueInt64()),
+		Keep:     uint64(data.Keep.ValueInt64()),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to purge stream: %s", err))
+		return
+	}
+	// 3. Write state
+	data.Purged = types.Int64Value(int64(purged))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *streamPurgeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The purge is an action, there is nothing to refresh
+	var data streamPurgeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *streamPurgeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every attribute requires replacement, so there is nothing to update
+	var data streamPurgeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *streamPurgeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Purged messages cannot be restored, removing the resource from the state is enough
+}
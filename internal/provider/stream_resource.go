@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"terraform-provider-nats/internal/nats"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -21,17 +24,20 @@ import (
 
 var _ resource.ResourceWithConfigure = &streamResource{}
 var _ resource.ResourceWithImportState = &streamResource{}
+var _ resource.ResourceWithModifyPlan = &streamResource{}
 
 func NewStreamResource() resource.Resource {
 	return &streamResource{}
 }
 
 type streamResource struct {
-	client nats.Client
+	client  nats.Client
+	planned *plannedResources
 }
 
 type streamResourceModel struct {
-	Name types.String `tfsdk:"name"`
+	Name   types.String `tfsdk:"name"`
+	Domain types.String `tfsdk:"domain"`
 
 	Subjects          []types.String `tfsdk:"subjects"`
 	Storage           types.String   `tfsdk:"storage"`
@@ -46,6 +52,34 @@ type streamResourceModel struct {
 	MaxAge            types.Int64    `tfsdk:"max_age"`
 	DuplicateWindow   types.Int64    `tfsdk:"duplicate_window"`
 	AllowDirect       types.Bool     `tfsdk:"allow_direct"`
+
+	SubjectsFilter      types.String `tfsdk:"subjects_filter"`
+	RestoreFromSnapshot types.String `tfsdk:"restore_from_snapshot"`
+	State               types.Object `tfsdk:"state"`
+}
+
+var streamReplicaAttrTypes = map[string]attr.Type{
+	"name":    types.StringType,
+	"current": types.BoolType,
+	"offline": types.BoolType,
+	"active":  types.Int64Type,
+	"lag":     types.Int64Type,
+}
+
+var streamStateAttrTypes = map[string]attr.Type{
+	"messages":       types.Int64Type,
+	"bytes":          types.Int64Type,
+	"first_seq":      types.Int64Type,
+	"first_ts":       types.StringType,
+	"last_seq":       types.Int64Type,
+	"last_ts":        types.StringType,
+	"consumer_count": types.Int64Type,
+	"num_subjects":   types.Int64Type,
+	"num_deleted":    types.Int64Type,
+	"subjects":       types.MapType{ElemType: types.Int64Type},
+	"cluster_name":   types.StringType,
+	"cluster_leader": types.StringType,
+	"replicas":       types.ListType{ElemType: types.ObjectType{AttrTypes: streamReplicaAttrTypes}},
 }
 
 func (r *streamResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -59,7 +93,12 @@ func (r *streamResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"name": schema.StringAttribute{ // Non-Editdable
 				Required: true,
 			},
+			"domain": schema.StringAttribute{ // Non-Editable
+				Description: "The JetStream domain of the stream. Defaults to the provider's jetstream_domain",
+				Optional:    true,
+			},
 			"subjects": schema.ListAttribute{ // Editable
+				Description: "The subjects of the messages the stream stores. They can't overlap with the subjects of the other streams of the domain, on the server or planned. To move subjects from another stream of the configuration, make this stream depend on it. Subjects of a destroyed stream can be used once it is destroyed, in a later apply",
 				ElementType: types.StringType,
 				Required:    true,
 			},
@@ -106,7 +145,7 @@ func (r *streamResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Validators:  []validator.Int64{infinityOrPositiveInt64Validator},
 			},
 			"max_bytes": schema.Int64Attribute{ // Editable
-				Description: "How many bytes the Stream may contain. Adheres to Discard Policy, removing oldest or refusing new messages if the Stream exceeds this size",
+				Description: "How many bytes the Stream may contain. Adheres to Discard Policy, removing oldest or refusing new messages if the Stream exceeds this size. Checked when planning against the account JetStream limits, together with the other streams planned in the domain",
 				Optional:    true,
 				Computed:    true,
 				Default:     int64default.StaticInt64(-1),
@@ -146,6 +185,103 @@ func (r *streamResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Computed:    true,
 				Default:     booldefault.StaticBool(true),
 			},
+			"subjects_filter": schema.StringAttribute{
+				Description: "If set, state.subjects reports the message count of each subject matching this filter, wildcards allowed",
+				Optional:    true,
+			},
+			"restore_from_snapshot": schema.StringAttribute{
+				Description: "Directory of a nats_stream_snapshot to restore the stream from when it is created. The stream config is then updated to match this resource. Ignored once the stream exists",
+				Optional:    true,
+			},
+			"state": schema.SingleNestedAttribute{
+				Description: "The runtime state of the stream as of the last refresh. Changes to it are not considered drift",
+				Computed:    true,
+				Attributes:  streamStateAttributes(),
+			},
+		},
+	}
+}
+
+// streamStateAttributes returns the attributes of the runtime state of a
+// stream, shared by the stream resource and data source.
+func streamStateAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"messages": schema.Int64Attribute{
+			Description: "Number of messages stored in the stream",
+			Computed:    true,
+		},
+		"bytes": schema.Int64Attribute{
+			Description: "Number of bytes stored in the stream",
+			Computed:    true,
+		},
+		"first_seq": schema.Int64Attribute{
+			Description: "Sequence number of the first message in the stream",
+			Computed:    true,
+		},
+		"first_ts": schema.StringAttribute{
+			Description: "Timestamp of the first message in the stream, in RFC3339 format",
+			Computed:    true,
+		},
+		"last_seq": schema.Int64Attribute{
+			Description: "Sequence number of the last message in the stream",
+			Computed:    true,
+		},
+		"last_ts": schema.StringAttribute{
+			Description: "Timestamp of the last message in the stream, in RFC3339 format",
+			Computed:    true,
+		},
+		"consumer_count": schema.Int64Attribute{
+			Description: "Number of consumers defined on the stream",
+			Computed:    true,
+		},
+		"num_subjects": schema.Int64Attribute{
+			Description: "Number of unique subjects in the stream",
+			Computed:    true,
+		},
+		"num_deleted": schema.Int64Attribute{
+			Description: "Number of messages deleted from the middle of the stream",
+			Computed:    true,
+		},
+		"subjects": schema.MapAttribute{
+			Description: "Message count per subject matching subjects_filter",
+			ElementType: types.Int64Type,
+			Computed:    true,
+		},
+		"cluster_name": schema.StringAttribute{
+			Description: "Name of the cluster hosting the stream",
+			Computed:    true,
+		},
+		"cluster_leader": schema.StringAttribute{
+			Description: "Name of the server leading the stream",
+			Computed:    true,
+		},
+		"replicas": schema.ListNestedAttribute{
+			Description: "The replicas following the leader",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Description: "Name of the server hosting the replica",
+						Computed:    true,
+					},
+					"current": schema.BoolAttribute{
+						Description: "Whether the replica is up to date with the leader",
+						Computed:    true,
+					},
+					"offline": schema.BoolAttribute{
+						Description: "Whether the replica is offline",
+						Computed:    true,
+					},
+					"active": schema.Int64Attribute{
+						Description: "Time since the replica was last seen by the leader, expressed in nanoseconds",
+						Computed:    true,
+					},
+					"lag": schema.Int64Attribute{
+						Description: "Number of operations the replica is behind the leader",
+						Computed:    true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -155,16 +291,18 @@ func (r *streamResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 
-	client, ok := req.ProviderData.(nats.Client)
+	data, ok := req.ProviderData.(*resourceData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected nats.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = data.Client
+	r.planned = data.planned
+	resp.Diagnostics.Append(data.checkServer()...)
 }
 
 func (r *streamResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -175,14 +313,20 @@ func (r *streamResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 	// 2. Create the resource
-	streamInfo, err := r.client.CreateStream(toStreamConfig(data))
-
+	client := r.client.WithDomain(data.Domain.ValueString())
+	var streamInfo nats.StreamInfo
+	var err error
+	if data.RestoreFromSnapshot.ValueString() == "" {
+		streamInfo, err = client.CreateStream(toStreamConfig(data))
+	} else {
+		streamInfo, err = restoreStream(client, data)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to create stream: %s", err))
 		return
 	}
 	// 3. Write state
-	data = fromStreamInfo(streamInfo)
+	data = fromStreamInfo(streamInfo).withLocalAttributes(data)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -194,7 +338,7 @@ func (r *streamResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 	// 2. Get the resource
-	streamInfo, err := r.client.GetStream(data.Name.ValueString())
+	streamInfo, err := r.client.WithDomain(data.Domain.ValueString()).GetStream(data.Name.ValueString(), data.SubjectsFilter.ValueString())
 	if err != nil {
 		if errors.Is(err, nats.ErrNotFound) {
 			resp.Diagnostics.AddWarning("Resource not found", "couldn't find the stream, possibly deleted outside terraform")
@@ -205,7 +349,7 @@ func (r *streamResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 	// 3. Write new state
-	data = fromStreamInfo(streamInfo)
+	data = fromStreamInfo(streamInfo).withLocalAttributes(data)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -230,15 +374,23 @@ func (r *streamResource) Update(ctx context.Context, req resource.UpdateRequest,
 		)
 		return
 	}
+	if plan.Domain != state.Domain {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("domain"),
+			"Cannot change stream domain",
+			"The stream lives in its JetStream domain. If you wish to change the domain, you must create a new stream.",
+		)
+		return
+	}
 	// 3. Update resource
-	streamInfo, err := r.client.UpdateStream(toStreamConfig(plan))
+	streamInfo, err := r.client.WithDomain(plan.Domain.ValueString()).UpdateStream(toStreamConfig(plan))
 	if err != nil {
 		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to update stream: %s", err))
 		return
 	}
 
 	// 3. Write new state
-	state = fromStreamInfo(streamInfo)
+	state = fromStreamInfo(streamInfo).withLocalAttributes(plan)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -250,13 +402,169 @@ func (r *streamResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 	// 2. Delete the resource
-	err := r.client.DeleteStream(state.Name.ValueString())
+	err := r.client.WithDomain(state.Domain.ValueString()).DeleteStream(state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to delete stream: %s", err))
 		return
 	}
 }
 
+func (r *streamResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check before the provider is configured
+	if r.client == nil {
+		return
+	}
+	// 1. Check the subjects against the other streams
+	resp.Diagnostics.Append(r.checkSubjects(ctx, req)...)
+	if req.Plan.Raw.IsNull() || resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Read planned & current reservations
+	planned, known, diags := getStorageReservation(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !known {
+		return
+	}
+	var current *storageReservation
+	if !req.State.Raw.IsNull() {
+		reservation, _, diags := getStorageReservation(ctx, req.State)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if reservation == planned {
+			return
+		}
+		current = &reservation
+	}
+	var name, domain types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("domain"), &domain)...)
+	if resp.Diagnostics.HasError() || name.IsUnknown() || domain.IsUnknown() {
+		return
+	}
+	// 3. Record the additional reservation for the other planned streams
+	if additional := planned.additionalBytes(current); additional > 0 {
+		r.planned.addReservation(domain.ValueString(), name.ValueString(), storageReservation{
+			Storage:  planned.Storage,
+			Replicas: planned.Replicas,
+			MaxBytes: additional / planned.Replicas,
+		})
+	}
+	// 4. Check against the account limits
+	accountInfo, err := r.client.WithDomain(domain.ValueString()).GetCachedAccountInfo()
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to check account limits", fmt.Sprintf("Failed to get account info: %s", err))
+		return
+	}
+	others := r.planned.otherReservations(domain.ValueString(), name.ValueString())
+	resp.Diagnostics.Append(checkStorageReservation(accountInfo, planned, current, others)...)
+}
+
+// checkSubjects checks the planned subjects don't overlap with the subjects
+// of the other streams of the domain, on the server or planned. The subjects
+// planned for a stream replace its subjects on the server, and a stream
+// planned to be destroyed frees them. A stream is recorded for the streams
+// planned after it once its subjects passed the check.
+func (r *streamResource) checkSubjects(ctx context.Context, req resource.ModifyPlanRequest) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var stateName, stateDomain types.String
+	if !req.State.Raw.IsNull() {
+		diags.Append(req.State.GetAttribute(ctx, path.Root("name"), &stateName)...)
+		diags.Append(req.State.GetAttribute(ctx, path.Root("domain"), &stateDomain)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+	if req.Plan.Raw.IsNull() {
+		r.planned.addStream(stateDomain.ValueString(), stateName.ValueString(), nil)
+		return diags
+	}
+	var name, domain types.String
+	var subjectList types.List
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("domain"), &domain)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("subjects"), &subjectList)...)
+	if diags.HasError() || name.IsUnknown() || domain.IsUnknown() {
+		return diags
+	}
+	if subjectList.IsUnknown() {
+		r.planned.addUnknownStream(domain.ValueString(), name.ValueString())
+		return diags
+	}
+	subjects := make([]string, 0, len(subjectList.Elements()))
+	for _, element := range subjectList.Elements() {
+		subject, ok := element.(types.String)
+		if !ok || subject.IsUnknown() {
+			r.planned.addUnknownStream(domain.ValueString(), name.ValueString())
+			return diags
+		}
+		subjects = append(subjects, subject.ValueString())
+	}
+	// Streams planned before this one replace their subjects on the server,
+	// streams planned with unknown subjects may release theirs. Overlaps with
+	// subjects that are on the server and not planned to change, or that are
+	// planned for another stream, are errors.
+	planned := r.planned.plannedStreams(domain.ValueString())
+	server := map[string][]string{}
+	streams, err := r.client.WithDomain(domain.ValueString()).ListCachedStreams()
+	if err != nil {
+		diags.AddWarning("Unable to check subject overlaps", fmt.Sprintf("Failed to list streams: %s", err))
+	}
+	for _, stream := range streams {
+		server[stream.Config.Name] = stream.Config.Subjects
+	}
+	delete(planned, name.ValueString())
+	delete(server, name.ValueString())
+	if stateDomain.ValueString() == domain.ValueString() {
+		delete(planned, stateName.ValueString())
+		delete(server, stateName.ValueString())
+	}
+	names := map[string]struct{}{}
+	for otherName := range server {
+		names[otherName] = struct{}{}
+	}
+	for otherName := range planned {
+		names[otherName] = struct{}{}
+	}
+	for _, subject := range subjects {
+		for _, otherName := range sortedKeys(names) {
+			other, isPlanned := planned[otherName]
+			for _, otherSubject := range server[otherName] {
+				switch {
+				case !nats.SubjectsOverlap(subject, otherSubject):
+				case isPlanned && !other.known:
+				case !isPlanned || slices.Contains(other.subjects, otherSubject):
+					diags.AddAttributeError(
+						path.Root("subjects"),
+						"Overlapping subjects",
+						fmt.Sprintf("Subject %q overlaps with subject %q of stream %s, streams can't share subjects. If the configuration moves the subject from stream %s, make this stream depend on it.", subject, otherSubject, otherName, otherName),
+					)
+				case other.subjects == nil:
+					diags.AddAttributeWarning(
+						path.Root("subjects"),
+						"Subject released by a destroyed stream",
+						fmt.Sprintf("Subject %q overlaps with subject %q of stream %s, which is planned to be destroyed. The apply fails if this stream is changed before stream %s is destroyed.", subject, otherSubject, otherName, otherName),
+					)
+				}
+			}
+			for _, otherSubject := range other.subjects {
+				if nats.SubjectsOverlap(subject, otherSubject) && !slices.Contains(server[otherName], otherSubject) {
+					diags.AddAttributeError(
+						path.Root("subjects"),
+						"Overlapping subjects",
+						fmt.Sprintf("Subject %q overlaps with subject %q planned for stream %s, streams can't share subjects.", subject, otherSubject, otherName),
+					)
+				}
+			}
+		}
+	}
+	if !diags.HasError() {
+		r.planned.addStream(domain.ValueString(), name.ValueString(), subjects)
+	}
+	return diags
+}
+
 func (r *streamResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
@@ -296,5 +604,72 @@ func fromStreamInfo(streamInfo nats.StreamInfo) streamResourceModel {
 		MaxAge:            types.Int64Value(int64(streamInfo.Config.MaxAge)),
 		DuplicateWindow:   types.Int64Value(int64(streamInfo.Config.Duplicates)),
 		AllowDirect:       types.BoolValue(streamInfo.Config.AllowDirect),
+		State:             fromStreamState(streamInfo),
+	}
+}
+
+// restoreStream restores the stream from its snapshot, then updates it to the
+// planned config. If the update fails, the restored stream is deleted again so
+// that no stream is left on the server without state.
+func restoreStream(client nats.Client, data streamResourceModel) (nats.StreamInfo, error) {
+	if _, err := client.RestoreStream(data.Name.ValueString(), data.RestoreFromSnapshot.ValueString()); err != nil {
+		return nats.StreamInfo{}, fmt.Errorf("failed to restore from snapshot: %w", err)
+	}
+	streamInfo, err := client.UpdateStream(toStreamConfig(data))
+	if err != nil {
+		if deleteErr := client.DeleteStream(data.Name.ValueString()); deleteErr != nil {
+			return nats.StreamInfo{}, fmt.Errorf("failed to update restored stream: %w, and failed to delete it: %w", err, deleteErr)
+		}
+		return nats.StreamInfo{}, fmt.Errorf("failed to update restored stream, it was deleted: %w", err)
+	}
+	return streamInfo, nil
+}
+
+// withLocalAttributes copies the attributes that are not part of the stream
+// info from the given model.
+func (data streamResourceModel) withLocalAttributes(from streamResourceModel) streamResourceModel {
+	data.Domain = from.Domain
+	data.SubjectsFilter = from.SubjectsFilter
+	data.RestoreFromSnapshot = from.RestoreFromSnapshot
+	return data
+}
+
+func fromStreamState(streamInfo nats.StreamInfo) types.Object {
+	subjects := types.MapNull(types.Int64Type)
+	if streamInfo.State.Subjects != nil {
+		counts := make(map[string]attr.Value, len(streamInfo.State.Subjects))
+		for subject, count := range streamInfo.State.Subjects {
+			counts[subject] = types.Int64Value(int64(count))
+		}
+		subjects = types.MapValueMust(types.Int64Type, counts)
+	}
+	clusterName, clusterLeader := types.StringNull(), types.StringNull()
+	replicas := []attr.Value{}
+	if streamInfo.Cluster != nil {
+		clusterName, clusterLeader = types.StringValue(streamInfo.Cluster.Name), types.StringValue(streamInfo.Cluster.Leader)
+		for _, replica := range streamInfo.Cluster.Replicas {
+			replicas = append(replicas, types.ObjectValueMust(streamReplicaAttrTypes, map[string]attr.Value{
+				"name":    types.StringValue(replica.Name),
+				"current": types.BoolValue(replica.Current),
+				"offline": types.BoolValue(replica.Offline),
+				"active":  types.Int64Value(int64(replica.Active)),
+				"lag":     types.Int64Value(int64(replica.Lag)),
+			}))
+		}
 	}
+	return types.ObjectValueMust(streamStateAttrTypes, map[string]attr.Value{
+		"messages":       types.Int64Value(int64(streamInfo.State.Msgs)),
+		"bytes":          types.Int64Value(int64(streamInfo.State.Bytes)),
+		"first_seq":      types.Int64Value(int64(streamInfo.State.FirstSeq)),
+		"first_ts":       timestampValue(streamInfo.State.FirstTime),
+		"last_seq":       types.Int64Value(int64(streamInfo.State.LastSeq)),
+		"last_ts":        timestampValue(streamInfo.State.LastTime),
+		"consumer_count": types.Int64Value(int64(streamInfo.State.Consumers)),
+		"num_subjects":   types.Int64Value(int64(streamInfo.State.NumSubjects)),
+		"num_deleted":    types.Int64Value(int64(streamInfo.State.NumDeleted)),
+		"subjects":       subjects,
+		"cluster_name":   clusterName,
+		"cluster_leader": clusterLeader,
+		"replicas":       types.ListValueMust(types.ObjectType{AttrTypes: streamReplicaAttrTypes}, replicas),
+	})
 }
@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &streamSnapshotResource{}
+
+func NewStreamSnapshotResource() resource.Resource {
+	return &streamSnapshotResource{}
+}
+
+type streamSnapshotResource struct {
+	client nats.Client
+}
+
+type streamSnapshotResourceModel struct {
+	StreamName  types.String            `tfsdk:"stream_name"`
+	Domain      types.String            `tfsdk:"domain"`
+	Path        types.String            `tfsdk:"path"`
+	NoConsumers types.Bool              `tfsdk:"no_consumers"`
+	CheckMsgs   types.Bool              `tfsdk:"check_msgs"`
+	Triggers    map[string]types.String `tfsdk:"triggers"`
+
+	Checksum types.String `tfsdk:"checksum"`
+	Size     types.Int64  `tfsdk:"size"`
+	Messages types.Int64  `tfsdk:"messages"`
+}
+
+func (r *streamSnapshotResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stream_snapshot"
+}
+
+func (r *streamSnapshotResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Stream snapshot resource, snapshots a stream to a local directory that nats_stream can be restored from. Changing any attribute, including triggers, takes a new snapshot. A missing or corrupted snapshot is taken again. Destroying the resource keeps the snapshot",
+		Attributes: map[string]schema.Attribute{
+			"stream_name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Description: "The JetStream domain of the stream. Defaults to the provider's jetstream_domain",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "The directory to write the snapshot to",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"no_consumers": schema.BoolAttribute{
+				Description: "If true, the consumers of the stream are not part of the snapshot",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"check_msgs": schema.BoolAttribute{
+				Description: "If true, the server checks the integrity of the messages before taking the snapshot",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Description: "Arbitrary values that, when changed, take a new snapshot",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"checksum": schema.StringAttribute{
+				Description: "SHA-256 checksum of the snapshot data",
+				Computed:    true,
+			},
+			"size": schema.Int64Attribute{
+				Description: "Size of the snapshot data, in bytes",
+				Computed:    true,
+			},
+			"messages": schema.Int64Attribute{
+				Description: "Number of messages in the stream when the snapshot was taken",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *streamSnapshotResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.Client
+	resp.Diagnostics.Append(data.checkServer()...)
+}
+
+func (r *streamSnapshotResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// 1. Read plan
+	var data streamSnapshotResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Snapshot the stream
+	snapshot, err := r.client.WithDomain(data.Domain.ValueString()).SnapshotStream(data.StreamName.ValueString(), data.Path.ValueString(), nats.SnapshotOptions{
+		NoConsumers: data.NoConsumers.ValueBool(),
+		CheckMsgs:   data.CheckMsgs.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to snapshot stream: %s", err))
+		return
+	}
+	// 3. Write state
+	data = data.withSnapshot(snapshot)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *streamSnapshotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// 1. Read current state
+	var data streamSnapshotResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Check the snapshot is still there and intact
+	snapshot, err := nats.ReadSnapshot(data.Path.ValueString())
+	if err != nil {
+		if errors.Is(err, nats.ErrNotFound) {
+			resp.Diagnostics.AddWarning("Resource not found", "couldn't find the snapshot, possibly deleted outside terraform")
+		} else {
+			resp.Diagnostics.AddWarning("Invalid snapshot", fmt.Sprintf("The snapshot will be taken again: %s", err))
+		}
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	// 3. Write new state
+	data = data.withSnapshot(snapshot)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *streamSnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every attribute requires replacement, so there is nothing to update
+	var data streamSnapshotResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *streamSnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Snapshots are kept for disaster recovery, removing the resource from the state is enough
+}
+
+func (data streamSnapshotResourceModel) withSnapshot(snapshot nats.Snapshot) streamSnapshotResourceModel {
+	data.Checksum = types.StringValue(snapshot.Checksum)
+	data.Size = types.Int64Value(snapshot.Size)
+	data.Messages = types.Int64Value(int64(snapshot.State.Msgs))
+	return data
+}
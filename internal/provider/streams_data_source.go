@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &streamsDataSource{}
+
+// NewStreamsDataSource creates a new streams datasource.
+func NewStreamsDataSource() datasource.DataSource {
+	return &streamsDataSource{}
+}
+
+type streamsDataSource struct {
+	client nats.Client
+}
+
+type streamsDataSourceModel struct {
+	Domain    types.String            `tfsdk:"domain"`
+	NameRegex types.String            `tfsdk:"name_regex"`
+	Subject   types.String            `tfsdk:"subject"`
+	Metadata  map[string]types.String `tfsdk:"metadata"`
+
+	Names   []types.String       `tfsdk:"names"`
+	Streams []streamSummaryModel `tfsdk:"streams"`
+}
+
+type streamSummaryModel struct {
+	Name          types.String            `tfsdk:"name"`
+	Subjects      []types.String          `tfsdk:"subjects"`
+	Storage       types.String            `tfsdk:"storage"`
+	Retention     types.String            `tfsdk:"retention"`
+	NumReplicas   types.Int64             `tfsdk:"num_replicas"`
+	MaxMsgs       types.Int64             `tfsdk:"max_msgs"`
+	MaxBytes      types.Int64             `tfsdk:"max_bytes"`
+	Metadata      map[string]types.String `tfsdk:"metadata"`
+	Messages      types.Int64             `tfsdk:"messages"`
+	Bytes         types.Int64             `tfsdk:"bytes"`
+	FirstSeq      types.Int64             `tfsdk:"first_seq"`
+	LastSeq       types.Int64             `tfsdk:"last_seq"`
+	ConsumerCount types.Int64             `tfsdk:"consumer_count"`
+}
+
+func (d *streamsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_streams"
+}
+
+func (d *streamsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Streams data source, lists the streams matching the given filters",
+		Attributes: map[string]schema.Attribute{
+			"domain": schema.StringAttribute{
+				Description: "The JetStream domain to list streams from. Defaults to the provider's jetstream_domain",
+				Optional:    true,
+			},
+			"name_regex": schema.StringAttribute{
+				Description: "A regular expression the stream names must match",
+				Optional:    true,
+			},
+			"subject": schema.StringAttribute{
+				Description: "Only list streams bound to subjects matching this subject, wildcards allowed. Filtered by the server",
+				Optional:    true,
+			},
+			"metadata": schema.MapAttribute{
+				Description: "Metadata key/value pairs the streams must all have",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"names": schema.ListAttribute{
+				Description: "The names of the matching streams, sorted",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"streams": schema.ListNestedAttribute{
+				Description: "Summary of the config and state of the matching streams, sorted by name",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"subjects": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"storage": schema.StringAttribute{
+							Description: "The storage type for stream data. Possible values: file, memory",
+							Computed:    true,
+						},
+						"retention": schema.StringAttribute{
+							Description: "The retention policy for the stream",
+							Computed:    true,
+						},
+						"num_replicas": schema.Int64Attribute{
+							Description: "How many replicas to keep for each message in a clustered JetStream",
+							Computed:    true,
+						},
+						"max_msgs": schema.Int64Attribute{
+							Description: "How many messages may be in the Stream",
+							Computed:    true,
+						},
+						"max_bytes": schema.Int64Attribute{
+							Description: "How many bytes the Stream may contain",
+							Computed:    true,
+						},
+						"metadata": schema.MapAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"messages": schema.Int64Attribute{
+							Description: "Number of messages stored in the stream",
+							Computed:    true,
+						},
+						"bytes": schema.Int64Attribute{
+							Description: "Number of bytes stored in the stream",
+							Computed:    true,
+						},
+						"first_seq": schema.Int64Attribute{
+							Description: "Sequence number of the first message in the stream",
+							Computed:    true,
+						},
+						"last_seq": schema.Int64Attribute{
+							Description: "Sequence number of the last message in the stream",
+							Computed:    true,
+						},
+						"consumer_count": schema.Int64Attribute{
+							Description: "Number of consumers defined on the stream",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *streamsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data.Client
+	resp.Diagnostics.Append(data.checkServer()...)
+}
+
+func (d *streamsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// 1. Read config
+	var config streamsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var nameRegex *regexp.Regexp
+	if config.NameRegex.ValueString() != "" {
+		var err error
+		nameRegex, err = regexp.Compile(config.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+			return
+		}
+	}
+	// 2. List the streams
+	streamInfos, err := d.client.WithDomain(config.Domain.ValueString()).ListStreams(config.Subject.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client error", fmt.Sprintf("Failed to list streams: %s", err))
+		return
+	}
+	// 3. Filter
+	state := config
+	state.Names = []types.String{}
+	state.Streams = []streamSummaryModel{}
+	sort.Slice(streamInfos, func(i, j int) bool { return streamInfos[i].Config.Name < streamInfos[j].Config.Name })
+	for _, streamInfo := range streamInfos {
+		if nameRegex != nil && !nameRegex.MatchString(streamInfo.Config.Name) {
+			continue
+		}
+		if !matchesMetadata(streamInfo.Config.Metadata, config.Metadata) {
+			continue
+		}
+		state.Names = append(state.Names, types.StringValue(streamInfo.Config.Name))
+		state.Streams = append(state.Streams, fromStreamInfoSummary(streamInfo))
+	}
+	// 4. Write state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func matchesMetadata(metadata map[string]string, filter map[string]types.String) bool {
+	for key, value := range filter {
+		actual, ok := metadata[key]
+		if !ok || actual != value.ValueString() {
+			return false
+		}
+	}
+	return true
+}
+
+func fromStreamInfoSummary(streamInfo nats.StreamInfo) streamSummaryModel {
+	var metadata map[string]types.String
+	if len(streamInfo.Config.Metadata) > 0 {
+		metadata = make(map[string]types.String, len(streamInfo.Config.Metadata))
+		for key, value := range streamInfo.Config.Metadata {
+			metadata[key] = types.StringValue(value)
+		}
+	}
+	return streamSummaryModel{
+		Name:          types.StringValue(streamInfo.Config.Name),
+		Subjects:      convertSlice(streamInfo.Config.Subjects, types.StringValue),
+		Storage:       types.StringValue(nats.FromStorageType(streamInfo.Config.Storage)),
+		Retention:     types.StringValue(nats.FromRetentionPolicy(streamInfo.Config.Retention)),
+		NumReplicas:   types.Int64Value(int64(streamInfo.Config.Replicas)),
+		MaxMsgs:       types.Int64Value(streamInfo.Config.MaxMsgs),
+		MaxBytes:      types.Int64Value(streamInfo.Config.MaxBytes),
+		Metadata:      metadata,
+		Messages:      types.Int64Value(int64(streamInfo.State.Msgs)),
+		Bytes:         types.Int64Value(int64(streamInfo.State.Bytes)),
+		FirstSeq:      types.Int64Value(int64(streamInfo.State.FirstSeq)),
+		LastSeq:       types.Int64Value(int64(streamInfo.State.LastSeq)),
+		ConsumerCount: types.Int64Value(int64(streamInfo.State.Consumers)),
+	}
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &subjectMatchesFunction{}
+
+func NewSubjectMatchesFunction() function.Function {
+	return &subjectMatchesFunction{}
+}
+
+type subjectMatchesFunction struct{}
+
+func (f *subjectMatchesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "subject_matches"
+}
+
+func (f *subjectMatchesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Checks a subject matches a pattern",
+		Description: "Returns whether the pattern matches the subject. A subject with wildcards matches if the pattern matches every subject it does, e.g. orders.* matches orders.> but not the opposite",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "subject",
+				Description: "The subject, wildcards allowed",
+			},
+			function.StringParameter{
+				Name:        "pattern",
+				Description: "The pattern, wildcards allowed",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *subjectMatchesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var subject, pattern string
+	resp.Error = req.Arguments.Get(ctx, &subject, &pattern)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(validSubjectArgument(0, subject), validSubjectArgument(1, pattern))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, nats.SubjectMatches(subject, pattern))
+}
+
+// validSubjectArgument returns an error for the argument at position if
+// subject isn't valid.
+func validSubjectArgument(position int64, subject string) *function.FuncError {
+	if !nats.ValidSubject(subject) {
+		return function.NewArgumentFuncError(position, fmt.Sprintf("Invalid subject: %q", subject))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &subjectsOverlapFunction{}
+
+func NewSubjectsOverlapFunction() function.Function {
+	return &subjectsOverlapFunction{}
+}
+
+type subjectsOverlapFunction struct{}
+
+func (f *subjectsOverlapFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "subjects_overlap"
+}
+
+func (f *subjectsOverlapFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Checks two subjects overlap",
+		Description: "Returns whether a subject is matched by both subjects, e.g. orders.* and *.new overlap on orders.new. Streams can't have overlapping subjects",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "a",
+				Description: "The first subject, wildcards allowed",
+			},
+			function.StringParameter{
+				Name:        "b",
+				Description: "The second subject, wildcards allowed",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *subjectsOverlapFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var a, b string
+	resp.Error = req.Arguments.Get(ctx, &a, &b)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(validSubjectArgument(0, a), validSubjectArgument(1, b))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, nats.SubjectsOverlap(a, b))
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &tokenizeSubjectFunction{}
+
+func NewTokenizeSubjectFunction() function.Function {
+	return &tokenizeSubjectFunction{}
+}
+
+type tokenizeSubjectFunction struct{}
+
+func (f *tokenizeSubjectFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "tokenize_subject"
+}
+
+func (f *tokenizeSubjectFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Splits a subject into its tokens",
+		Description: "Returns the dot separated tokens of the subject, e.g. [\"orders\", \"*\", \"eu\"] for orders.*.eu. Use join(\".\", tokens) to build the subject back",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "subject",
+				Description: "The subject, wildcards allowed",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *tokenizeSubjectFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var subject string
+	resp.Error = req.Arguments.Get(ctx, &subject)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = validSubjectArgument(0, subject)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, nats.TokenizeSubject(subject))
+}
@@ -0,0 +1,292 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-nats/internal/nats"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.ResourceWithConfigure  = &userResource{}
+	_ resource.ResourceWithModifyPlan = &userResource{}
+)
+
+func NewUserResource() resource.Resource {
+	return &userResource{}
+}
+
+// userResource needs no server connection, the JWT is issued locally.
+type userResource struct {
+	planned *plannedResources
+}
+
+type userResourceModel struct {
+	Name        types.String   `tfsdk:"name"`
+	Seed        types.String   `tfsdk:"seed"`
+	SigningSeed types.String   `tfsdk:"signing_seed"`
+	Account     types.String   `tfsdk:"account"`
+	Scope       types.String   `tfsdk:"scope"`
+	AccountJWT  types.String   `tfsdk:"account_jwt"`
+	Tags        []types.String `tfsdk:"tags"`
+	Expires     types.String   `tfsdk:"expires"`
+
+	PubAllow            []types.String           `tfsdk:"pub_allow"`
+	PubDeny             []types.String           `tfsdk:"pub_deny"`
+	SubAllow            []types.String           `tfsdk:"sub_allow"`
+	SubDeny             []types.String           `tfsdk:"sub_deny"`
+	ResponsePermissions *responsePermissionModel `tfsdk:"response_permissions"`
+	ConnectionTypes     []types.String           `tfsdk:"connection_types"`
+	SourceNetworks      []types.String           `tfsdk:"source_networks"`
+	TimeRestrictions    []timeRangeModel         `tfsdk:"time_restrictions"`
+	Locale              types.String             `tfsdk:"locale"`
+	BearerToken         types.Bool               `tfsdk:"bearer_token"`
+	MaxSubscriptions    types.Int64              `tfsdk:"max_subscriptions"`
+	MaxData             types.Int64              `tfsdk:"max_data"`
+	MaxPayload          types.Int64              `tfsdk:"max_payload"`
+
+	PublicKey types.String `tfsdk:"public_key"`
+	JWT       types.String `tfsdk:"jwt"`
+	Creds     types.String `tfsdk:"creds"`
+}
+
+func (r *userResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (r *userResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	permissions := userPermissionAttributes()
+	permissionPaths := make([]path.Expression, 0, len(permissions))
+	for name := range permissions {
+		permissionPaths = append(permissionPaths, path.MatchRoot(name))
+	}
+	attributes := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Required: true,
+		},
+		"seed": schema.StringAttribute{
+			Description: "The seed of the user nkey",
+			Required:    true,
+			Sensitive:   true,
+		},
+		"signing_seed": schema.StringAttribute{
+			Description: "The seed of the account nkey, or of one of the account signing keys, to sign the user JWT with",
+			Required:    true,
+			Sensitive:   true,
+		},
+		"account": schema.StringAttribute{
+			Description: "The public key of the account, required when signing with an account signing key",
+			Optional:    true,
+		},
+		"scope": schema.StringAttribute{
+			Description: "The role of the scoped signing key to issue the user under, signing_seed must be the seed of its key. The user gets the permissions and limits of the scope template and can't set its own",
+			Optional:    true,
+			Validators: []validator.String{
+				stringvalidator.AlsoRequires(path.MatchRoot("account_jwt")),
+				stringvalidator.ConflictsWith(permissionPaths...),
+			},
+		},
+		"account_jwt": schema.StringAttribute{
+			Description: "The account JWT to look up the scope in, e.g. the jwt of a nats_account",
+			Optional:    true,
+		},
+		"tags": schema.ListAttribute{
+			Description: "Tags of the user, e.g. team:billing, used by the {{tag(team)}} templates of scoped signing keys",
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+		"expires": schema.StringAttribute{
+			Description: "Expiry of the JWT, in RFC3339 format. Defaults to no expiry",
+			Optional:    true,
+			Validators:  []validator.String{rfc3339Validator{}},
+		},
+		"public_key": schema.StringAttribute{
+			Description: "The public key of the user",
+			Computed:    true,
+		},
+		"jwt": schema.StringAttribute{
+			Description: "The encoded user JWT",
+			Computed:    true,
+		},
+		"creds": schema.StringAttribute{
+			Description: "The content of the user .creds file, holding the JWT and the seed",
+			Computed:    true,
+			Sensitive:   true,
+		},
+	}
+	for name, attribute := range permissions {
+		attributes[name] = attribute
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "User resource, issues a user JWT and its creds file locally without connecting to the server. The JWT is only signed again when its claims change",
+		Attributes:          attributes,
+	}
+}
+
+func (r *userResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.planned = data.planned
+}
+
+func (r *userResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	// 1. Record the user for the auth callouts of the accounts
+	var seed types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("seed"), &seed)...)
+	if key, err := nats.ParseNKeySeed(seed.ValueString()); !seed.IsUnknown() && err == nil {
+		r.planned.addPublicKey(key.PublicKey)
+	}
+	// 2. Issue the JWT, which can only be issued once every claim is known
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+	var plan userResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	token, publicKey, err := nats.IssueUserJWT(toUserSpec(plan), plan.Seed.ValueString(), plan.SigningSeed.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid user", fmt.Sprintf("Failed to issue user JWT: %s", err))
+		return
+	}
+	// The JWT of the state is kept while its claims hold, a new JWT is only
+	// signed when applying, as every signing gives a different token.
+	plan.PublicKey = types.StringValue(publicKey)
+	plan.JWT, plan.Creds = types.StringUnknown(), types.StringUnknown()
+	if !req.State.Raw.IsNull() {
+		var state userResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if nats.SameClaims(state.JWT.ValueString(), token) {
+			plan.JWT, plan.Creds = state.JWT, state.Creds
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// 1. Read plan
+	var data userResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Issue the JWT
+	resp.Diagnostics.Append(data.issue()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 3. Write state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The JWT only lives in the state, there is nothing to refresh
+	var data userResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// 1. Read plan
+	var data userResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 2. Issue the JWT, unless the JWT of the state was kept when planning
+	resp.Diagnostics.Append(data.issue()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 3. Write new state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The JWT only lives in the state, removing the resource from the state is enough
+}
+
+func (data *userResourceModel) issue() diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !data.JWT.IsUnknown() {
+		return diags
+	}
+	token, publicKey, err := nats.IssueUserJWT(toUserSpec(*data), data.Seed.ValueString(), data.SigningSeed.ValueString())
+	if err != nil {
+		diags.AddError("Invalid user", fmt.Sprintf("Failed to issue user JWT: %s", err))
+		return diags
+	}
+	data.PublicKey = types.StringValue(publicKey)
+	data.JWT = types.StringValue(token)
+	return data.formatCreds()
+}
+
+func (data *userResourceModel) formatCreds() diag.Diagnostics {
+	var diags diag.Diagnostics
+	creds, err := nats.FormatCreds(data.JWT.ValueString(), data.Seed.ValueString())
+	if err != nil {
+		diags.AddError("Invalid user", err.Error())
+		return diags
+	}
+	data.Creds = types.StringValue(creds)
+	return diags
+}
+
+func toUserSpec(data userResourceModel) nats.UserSpec {
+	spec := nats.UserSpec{
+		Name:    data.Name.ValueString(),
+		Account: data.Account.ValueString(),
+		Permissions: toUserPermissions(userPermissionsModel{
+			PubAllow:            data.PubAllow,
+			PubDeny:             data.PubDeny,
+			SubAllow:            data.SubAllow,
+			SubDeny:             data.SubDeny,
+			ResponsePermissions: data.ResponsePermissions,
+			ConnectionTypes:     data.ConnectionTypes,
+			SourceNetworks:      data.SourceNetworks,
+			TimeRestrictions:    data.TimeRestrictions,
+			Locale:              data.Locale,
+			BearerToken:         data.BearerToken,
+			MaxSubscriptions:    data.MaxSubscriptions,
+			MaxData:             data.MaxData,
+			MaxPayload:          data.MaxPayload,
+		}),
+		Tags:       convertSlice(data.Tags, (types.String).ValueString),
+		Scope:      data.Scope.ValueString(),
+		AccountJWT: data.AccountJWT.ValueString(),
+	}
+	if !data.Expires.IsNull() {
+		// The value is validated as RFC3339
+		spec.Expires, _ = time.Parse(time.RFC3339, data.Expires.ValueString())
+	}
+	return spec
+}
@@ -1,6 +1,22 @@
 package provider
 
-import "github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"terraform-provider-nats/internal/nats"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
 
 var infinityOrPositiveInt64Validator = int64validator.Any(int64validator.OneOf(-1), int64validator.AtLeast(1))
 
@@ -11,3 +27,144 @@ func convertSlice[S any, D any](in []S, fn func(S) D) []D {
 	}
 	return out
 }
+
+// dataSourceAttributes returns the data source counterparts of computed
+// resource attributes, for the read-only attributes shared by a resource and
+// its data source.
+func dataSourceAttributes(attributes map[string]resourceschema.Attribute) map[string]datasourceschema.Attribute {
+	converted := make(map[string]datasourceschema.Attribute, len(attributes))
+	for name, attribute := range attributes {
+		description := attribute.GetDescription()
+		switch attribute := attribute.(type) {
+		case resourceschema.StringAttribute:
+			converted[name] = datasourceschema.StringAttribute{Description: description, Computed: true}
+		case resourceschema.Int64Attribute:
+			converted[name] = datasourceschema.Int64Attribute{Description: description, Computed: true}
+		case resourceschema.BoolAttribute:
+			converted[name] = datasourceschema.BoolAttribute{Description: description, Computed: true}
+		case resourceschema.MapAttribute:
+			converted[name] = datasourceschema.MapAttribute{Description: description, ElementType: attribute.ElementType, Computed: true}
+		case resourceschema.ListNestedAttribute:
+			converted[name] = datasourceschema.ListNestedAttribute{
+				Description:  description,
+				Computed:     true,
+				NestedObject: datasourceschema.NestedAttributeObject{Attributes: dataSourceAttributes(attribute.NestedObject.Attributes)},
+			}
+		default:
+			panic(fmt.Sprintf("unsupported computed attribute %s of type %T", name, attribute))
+		}
+	}
+	return converted
+}
+
+// setFromResourceModel sets the state of a data source from the model of the
+// matching resource, leaving out the resource attributes the data source
+// doesn't have.
+func setFromResourceModel(ctx context.Context, r resource.Resource, model any, state *tfsdk.State) diag.Diagnostics {
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	resourceState := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := resourceState.Set(ctx, model)
+	if diags.HasError() {
+		return diags
+	}
+	var values map[string]tftypes.Value
+	if err := resourceState.Raw.As(&values); err != nil {
+		diags.AddError("Unexpected Resource State", fmt.Sprintf("Failed to read the resource state: %s. Please report this issue to the provider developers.", err))
+		return diags
+	}
+	attributes := state.Schema.GetAttributes()
+	for name := range values {
+		if _, ok := attributes[name]; !ok {
+			delete(values, name)
+		}
+	}
+	state.Raw = tftypes.NewValue(state.Schema.Type().TerraformType(ctx), values)
+	return diags
+}
+
+// sortedKeys returns the keys of the map in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// timestampValue formats t in RFC3339, or returns null for the zero time.
+func timestampValue(t time.Time) types.String {
+	if t.IsZero() {
+		return types.StringNull()
+	}
+	return types.StringValue(t.Format(time.RFC3339Nano))
+}
+
+// rfc3339Validator checks that a string attribute is a timestamp in RFC3339
+// format.
+type rfc3339Validator struct{}
+
+func (v rfc3339Validator) Description(ctx context.Context) string {
+	return "value must be a timestamp in RFC3339 format"
+}
+
+func (v rfc3339Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid timestamp", fmt.Sprintf("Attribute %s %s: %s", req.Path, v.Description(ctx), err))
+	}
+}
+
+// permissionTemplateValidator checks that a string attribute is a subject of
+// a scoped signing key template, with valid template functions.
+type permissionTemplateValidator struct{}
+
+func (v permissionTemplateValidator) Description(ctx context.Context) string {
+	return "value must be a subject, optionally with {{name()}}, {{subject()}}, {{account-name()}}, {{account-subject()}}, {{tag(<name>)}} or {{account-tag(<name>)}} templates"
+}
+
+func (v permissionTemplateValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v permissionTemplateValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := nats.ValidatePermissionTemplate(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid permission template", fmt.Sprintf("Attribute %s %s: %s", req.Path, v.Description(ctx), err))
+	}
+}
+
+// publicKeyValidator checks that a string attribute is a public nkey of the
+// given type.
+type publicKeyValidator struct {
+	keyType string
+}
+
+func (v publicKeyValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be a public %s nkey", v.keyType)
+}
+
+func (v publicKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v publicKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if !nats.ValidPublicKey(req.ConfigValue.ValueString(), v.keyType) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid public key", fmt.Sprintf("Attribute %s %s, got: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString()))
+	}
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-nats/internal/nats"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &validSubjectFunction{}
+
+func NewValidSubjectFunction() function.Function {
+	return &validSubjectFunction{}
+}
+
+type validSubjectFunction struct{}
+
+func (f *validSubjectFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "valid_subject"
+}
+
+func (f *validSubjectFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Checks a subject is valid",
+		Description: "Returns whether the subject is valid: dot separated tokens that are not empty and have no whitespace, * standing alone in a token and > only as the last token",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "subject",
+				Description: "The subject, wildcards allowed",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *validSubjectFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var subject string
+	resp.Error = req.Arguments.Get(ctx, &subject)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, nats.ValidSubject(subject))
+}